@@ -2,18 +2,27 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/aravindcm49/dir-init/internal/config"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var showHiddenCategories bool
+
 var categoriesCmd = &cobra.Command{
 	Use:   "categories",
 	Short: "List all available categories",
 	Long: `List all available categories with description and word count.
 
+Categories are read from the config file and listed in the order given by
+their "order" field, then alphabetically. Hidden categories are not part of
+"all" and are only listed with --hidden.
+
 Examples:
-  dir-init categories`,
+  dir-init categories
+  dir-init categories --hidden`,
 	Run: func(cmd *cobra.Command, args []string) {
 		printCategories()
 	},
@@ -21,97 +30,76 @@ Examples:
 
 func init() {
 	rootCmd.AddCommand(categoriesCmd)
+
+	categoriesCmd.Flags().BoolVar(&showHiddenCategories, "hidden", false, "Also list hidden categories")
 }
 
-func printCategories() {
-	fmt.Println()
-	color.Cyan("Available Categories:\n")
+// defaultCategoryDescriptions describes the default categories of config
+// files written before categories had a description
+var defaultCategoryDescriptions = map[string]string{
+	"food":       "Food, cooking and beverage words",
+	"animals":    "Animals and nature words",
+	"pop":        "Pop culture, fantasy and creative arts words",
+	"silly":      "Silly, funny and absurd words",
+	"dev":        "Development tools and programming words",
+	"adjectives": "Adjectives for composed names",
+}
 
-	categories := map[string]struct {
-		Description string
-		WordCount   int
-	}{
-		"tech": {
-			Description: "Technology & programming related words",
-			WordCount:   len(getTechWords()),
-		},
-		"food": {
-			Description: "Food, cooking and beverage words",
-			WordCount:   len(getFoodWords()),
-		},
-		"animals": {
-			Description: "Animals and nature words",
-			WordCount:   len(getAnimalWords()),
-		},
-		"pop": {
-			Description: "Pop culture, fantasy and creative arts words",
-			WordCount:   len(getPopWords()),
-		},
-		"silly": {
-			Description: "Silly, funny and absurd words",
-			WordCount:   len(getSillyWords()),
-		},
-		"dev": {
-			Description: "Development tools and programming words",
-			WordCount:   len(getDevWords()),
-		},
-		"all": {
-			Description: "All categories combined",
-			WordCount:   getTotalWordCount(),
-		},
+// categoryDescription returns the description a category has in the config,
+// or else the built-in one of a default category
+func categoryDescription(name, description string) string {
+	if description != "" {
+		return description
 	}
-
-	for name, info := range categories {
-		fmt.Printf("%s%s%s", color.BlueString("• "), color.YellowString(name), color.BlueString(" - "))
-		fmt.Printf("%s", info.Description)
-		if name != "all" {
-			fmt.Printf(" (%d words)", info.WordCount)
-		}
-		fmt.Println()
-
-		if name != "all" {
-			fmt.Printf("  %s\n", color.New(color.FgWhite).Sprint("Example: tech-1234, food-goofy, animals-abc2"))
-		}
+	if description, ok := defaultCategoryDescriptions[name]; ok {
+		return description
 	}
-
-	fmt.Println()
-	color.Green("Use 'dir-init generate -c <category>' to generate names from a specific category.")
+	return "Custom category"
 }
 
-// Helper functions to get word counts (simplified for now)
-func getTechWords() []string {
-	// This would normally import from the categories package
-	// For now, return a placeholder count
-	return make([]string, 100) // Approximately 100 tech words
+// categoryExamples returns the example words of a category, or its first words
+func categoryExamples(examples, words []string, n int) []string {
+	if len(examples) > 0 {
+		return examples
+	}
+	return words[:min(n, len(words))]
 }
 
-func getFoodWords() []string {
-	return make([]string, 80) // Approximately 80 food words
-}
+func printCategories() {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		color.Red("❌ Error loading config: %v\n", err)
+		return
+	}
 
-func getAnimalWords() []string {
-	return make([]string, 120) // Approximately 120 animal words
-}
+	fmt.Println()
+	color.Cyan("Available Categories:\n")
 
-func getPopWords() []string {
-	return make([]string, 90) // Approximately 90 pop words
-}
+	all := make(map[string]struct{})
+	for _, name := range cfg.CategoryNames(showHiddenCategories) {
+		cat := cfg.Categories[name]
+		if !cat.Hidden {
+			for _, w := range cat.Words {
+				all[w] = struct{}{}
+			}
+		}
 
-func getSillyWords() []string {
-	return make([]string, 110) // Approximately 110 silly words
-}
+		label := color.YellowString(name)
+		if cat.Hidden {
+			label += color.New(color.FgWhite).Sprint(" (hidden)")
+		}
 
-func getDevWords() []string {
-	return make([]string, 95) // Approximately 95 dev words
-}
+		fmt.Printf("%s%s%s", color.BlueString("• "), label, color.BlueString(" - "))
+		fmt.Printf("%s (%d words)\n", categoryDescription(name, cat.Description), len(cat.Words))
+		if examples := categoryExamples(cat.Examples, cat.Words, 3); len(examples) > 0 {
+			fmt.Printf("  %s\n", color.New(color.FgWhite).Sprintf("Example: %s", strings.Join(examples, ", ")))
+		}
+	}
 
-func getTotalWordCount() int {
-	tech := len(getTechWords())
-	food := len(getFoodWords())
-	animals := len(getAnimalWords())
-	pop := len(getPopWords())
-	silly := len(getSillyWords())
-	dev := len(getDevWords())
+	fmt.Printf("%s%s%s", color.BlueString("• "), color.YellowString("all"), color.BlueString(" - "))
+	fmt.Printf("All visible categories combined (%d distinct words)\n", len(all))
 
-	return tech + food + animals + pop + silly + dev
-}
\ No newline at end of file
+	fmt.Println()
+	color.Green("Use 'dir-init generate -c <category>' to generate names from a specific category.")
+	color.Green("Use 'dir-init stats' to see how many distinct names each category can produce.")
+}
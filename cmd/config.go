@@ -4,10 +4,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 
 	"github.com/aravindcm49/dir-init/internal/config"
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
 	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
 func init() {
@@ -19,6 +25,10 @@ func init() {
 	configCmd.AddCommand(configEditCmd)
 	configCmd.AddCommand(configAddCmd)
 	configCmd.AddCommand(configRemoveCmd)
+	configCmd.AddCommand(configMigrateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
 
 	// Add subcommands for config add
 	configAddCmd.AddCommand(configAddTechStackCmd)
@@ -29,8 +39,30 @@ func init() {
 	configRemoveCmd.AddCommand(configRemoveTechStackCmd)
 	configRemoveCmd.AddCommand(configRemoveFrameworkCmd)
 	configRemoveCmd.AddCommand(configRemoveWordCmd)
+
+	configValidateCmd.Flags().BoolVar(&validateStrict, "strict", false, "Exit with status 1 on warnings too")
+	configMigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show the changes without writing the config file")
+	configMigrateCmd.Flags().StringSliceVar(&migrateStacks, "stack", nil, "Place a tech stack as <code>=frontend or <code>=backend (repeatable)")
+	configShowCmd.Flags().BoolVar(&showOrigin, "origin", false, "Show the merged settings with the layer each value comes from")
+	configExportCmd.Flags().StringVar(&exportFormat, "format", config.FormatYAML, "Output format (yaml, json, toml)")
+	configExportCmd.Flags().StringSliceVar(&exportSections, "section", nil, "Only export these sections, e.g. categories.food,frontends (repeatable)")
+	configImportCmd.Flags().StringVar(&importStrategy, "strategy", config.ImportMerge, "How imported values combine with yours (merge, replace, skip-existing)")
+	configImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show the changes without writing the config file")
+	configImportCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "Apply the changes without asking")
 }
 
+var (
+	showOrigin     bool
+	validateStrict bool
+	migrateDryRun  bool
+	migrateStacks  []string
+	exportFormat   string
+	exportSections []string
+	importStrategy string
+	importDryRun   bool
+	importYes      bool
+)
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage custom word collections",
@@ -41,7 +73,7 @@ var configInitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize config file with examples",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := config.InitConfig(); err != nil {
+		if err := initConfigFile(); err != nil {
 			color.Red("❌ Error: %v\n", err)
 			return
 		}
@@ -60,7 +92,26 @@ var configPathCmd = &cobra.Command{
 var configShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show loaded collections",
+	Long: `Show the collections of the merged config.
+
+With --origin the merged config is printed as YAML, every value commented with
+the layer it comes from: system, user, project, env or flags.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if showOrigin {
+			layered, err := config.LoadLayered(flagLayers(cmd)...)
+			if err != nil {
+				color.Red("❌ Error loading config: %v\n", err)
+				return
+			}
+			data, err := layered.Annotated()
+			if err != nil {
+				color.Red("❌ Error: %v\n", err)
+				return
+			}
+			fmt.Print(string(data))
+			return
+		}
+
 		cfg, err := config.LoadConfig()
 		if err != nil {
 			color.Red("❌ Error loading config: %v\n", err)
@@ -92,10 +143,18 @@ var configShowCmd = &cobra.Command{
 		// Show categories
 		if len(cfg.Categories) > 0 {
 			green.Println("\nCategory Words:")
-			for category, words := range cfg.Categories {
-				yellow.Printf("  %s:\n", category)
-				for _, word := range words {
-					fmt.Printf("    • %s\n", word)
+			for category, cat := range cfg.Categories {
+				if cat.Weight > 0 {
+					yellow.Printf("  %s (weight %g):\n", category, cat.Weight)
+				} else {
+					yellow.Printf("  %s:\n", category)
+				}
+				for _, word := range cat.Words {
+					if weight, ok := cat.WordWeights[word]; ok {
+						fmt.Printf("    • %s (weight %g)\n", word, weight)
+					} else {
+						fmt.Printf("    • %s\n", word)
+					}
 				}
 			}
 		}
@@ -106,33 +165,331 @@ var configShowCmd = &cobra.Command{
 	},
 }
 
+// flagKeys are the config keys of the flags named differently; "no-" flags
+// set their key to the opposite value
+var flagKeys = map[string]string{
+	"emoji":      "emojis",
+	"no-numbers": "include_numbers",
+	"no-sfw":     "safe_for_work",
+}
+
+// flagLayers turns every persistent flag that was set into a config layer, so
+// that 'config show --origin' shows what it overrides. Flags without a config
+// key are listed among the layers without values.
+func flagLayers(cmd *cobra.Command) []config.Layer {
+	var layers []config.Layer
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		// --config is shown as the source of the user layer
+		if f.Name == "config" || cmd.Root().PersistentFlags().Lookup(f.Name) == nil {
+			return
+		}
+
+		key, value := flagKeys[f.Name], f.Value.String()
+		if key == "" {
+			key = strings.ReplaceAll(f.Name, "-", "_")
+		}
+		if strings.HasPrefix(f.Name, "no-") && f.Value.Type() == "bool" {
+			negated, _ := strconv.ParseBool(value)
+			value = strconv.FormatBool(!negated)
+		}
+		if !config.IsSettingKey(key) {
+			key, value = "", f.Value.String()
+		}
+		layers = append(layers, config.FlagLayer("--"+f.Name, key, value))
+	})
+	return layers
+}
+
 var configValidateCmd = &cobra.Command{
 	Use:   "validate",
-	Short: "Validate config syntax",
+	Short: "Validate the config files",
+	Long: `Validate the system, user and project config files, the word list files
+and the config merged from them.
+
+Besides YAML syntax, validate reports unknown keys, empty, invalid and
+duplicate codes, invalid and duplicate words, categories without words and
+settings the generator rejects. Words or codes in several lists and words
+containing '-' are reported as warnings. Every problem is printed as
+file:line:column, and the command exits with status 1 when there are errors,
+or with --strict also when there are warnings, so it can run as a pre-commit
+hook.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		configPath := config.GetConfigPath()
+		// Nothing is written: older files are checked as they are on disk
+		if _, err := os.Stat(config.Default().UserConfigPath()); os.IsNotExist(err) {
+			color.Yellow("⚠️  Config file not found at: %s, checking the built-in defaults\n", config.GetConfigPath())
+		}
+
+		problems := config.Default().Validate()
+
+		// settings such as the layout or the case style, checked by the generator
+		if cfg, err := dirinit.LoadConfig(); err == nil {
+			if _, err := dirinit.New(dirinit.WithConfig(cfg)); err != nil {
+				problems = append(problems, config.Problem{File: "merged config", Message: err.Error()})
+			}
+		}
+
+		warnings := 0
+		for _, p := range problems {
+			if p.Warning {
+				warnings++
+				color.Yellow("%s\n", p)
+			} else {
+				color.Red("%s\n", p)
+			}
+		}
 
-		// Check if file exists
-		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			color.Yellow("⚠️  Config file not found at: %s\n", configPath)
+		if errors := len(problems) - warnings; errors > 0 {
+			color.Red("❌ Config validation failed: %s, %s\n", countOf(errors, "error"), countOf(warnings, "warning"))
+			os.Exit(1)
+		}
+		if warnings > 0 {
+			color.Yellow("⚠️  Config is valid with %s\n", countOf(warnings, "warning"))
+			if validateStrict {
+				os.Exit(1)
+			}
 			return
 		}
+		color.Green("✓ Config file is valid\n")
+	},
+}
+
+// countOf formats a count with a singular or plural noun, such as "1 error"
+func countOf(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print a JSON Schema of the config file",
+	Long: `Print a JSON Schema of the config file, for editors that complete and check
+YAML against a schema. With the YAML language server, save it and point the
+config file at it with a first line such as:
 
-		// Try to load config
-		_, err := config.LoadConfig()
+  # yaml-language-server: $schema=./dir-init.schema.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		schema, err := config.JSONSchema()
 		if err != nil {
-			color.Red("❌ Config validation failed: %v\n", err)
+			color.Red("❌ Error: %v\n", err)
 			return
 		}
+		fmt.Println(string(schema))
+	},
+}
 
-		color.Green("✓ Config file is valid\n")
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export your config as YAML, JSON or TOML",
+	Long: `Export your config file, with the words of your word list files, to share
+it with 'dir-init config import'.
+
+Sections limit the export to top-level keys such as categories, frontends,
+backends or blocklist, to "settings" for single values such as case and
+layout, or to single categories as categories.<name>.
+
+Examples:
+  dir-init config export > my-config.yaml
+  dir-init config export --section categories.food --format json > food.json
+  dir-init config export --section frontends,backends --format toml > stacks.toml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := config.ParseFormat(exportFormat)
+		if err != nil {
+			color.Red("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadUserConfig()
+		if err != nil {
+			color.Red("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		data, err := cfg.Export(format, exportSections...)
+		if err != nil {
+			color.Red("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(data)
 	},
 }
 
+var configImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a YAML, JSON or TOML config into yours",
+	Long: `Import a config file, such as one written by 'dir-init config export', into
+your config file. The format is detected by the extension: .json, .toml, and
+YAML for anything else.
+
+Strategies:
+  merge          lists such as words and frontends are extended, other values
+                 replaced, as a project config would (default)
+  replace        each section and category of the file replaces yours
+  skip-existing  only words, codes, categories and settings you do not have are added
+
+The changes are shown before they are saved, and confirmed when run in a
+terminal. Files with errors that 'dir-init config validate' would report are
+not imported.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := args[0]
+
+		cfg, err := config.LoadUserConfig()
+		if err != nil {
+			color.Red("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		result, changes, err := cfg.Import(path, importStrategy)
+		if err != nil {
+			color.Red("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(changes) == 0 {
+			color.Green("✓ Nothing to import: your config already has everything in %s\n", path)
+			return
+		}
+
+		color.Yellow("Importing %s with --strategy %s changes %s:\n", path, importStrategy, config.GetConfigPath())
+		for _, change := range changes {
+			switch change.Op {
+			case "+":
+				color.Green("  %s\n", change)
+			case "-":
+				color.Red("  %s\n", change)
+			default:
+				color.Yellow("  %s\n", change)
+			}
+		}
+
+		if importDryRun {
+			return
+		}
+		if !importYes && term.IsTerminal(int(os.Stdin.Fd())) {
+			confirm := promptui.Prompt{Label: "Apply these changes", IsConfirm: true}
+			if _, err := confirm.Run(); err != nil {
+				color.Yellow("Import cancelled\n")
+				return
+			}
+		}
+
+		if err := config.SaveConfig(result); err != nil {
+			color.Red("❌ Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+		color.Green("✓ Imported %s from %s\n", countOf(len(changes), "change"), path)
+	},
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the config file to the current schema version",
+	Long: fmt.Sprintf(`Upgrade the config file to schema version %d.
+
+Older config files are upgraded in memory when they are loaded, but only this
+command rewrites them, after saving a copy next to them as
+config.yaml.v<version>-<time>.bak. It also moves the files of an older
+~/.dir-init directory to their XDG locations. Run it with --dry-run first to
+see what the upgrade changes.
+
+Version 1 moves tech_stacks and frameworks to frontends and backends. Stacks
+and frameworks dir-init does not know are placed with --stack, or asked for
+when run in a terminal; until then the file keeps its version.`, config.CurrentVersion),
+	Run: func(cmd *cobra.Command, args []string) {
+		stacks := make(config.StackSides)
+		for _, mapping := range migrateStacks {
+			code, side, ok := strings.Cut(mapping, "=")
+			key, err := config.ParseStackSide(side)
+			if !ok || code == "" || err != nil {
+				color.Red("❌ Error: invalid --stack '%s' (use <code>=frontend or <code>=backend)\n", mapping)
+				os.Exit(1)
+			}
+			stacks[code] = key
+		}
+
+		if !migrateDryRun {
+			if err := moveLegacyFiles(); err != nil {
+				color.Red("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		result, err := config.Default().MigrateSchema(migrateDryRun, stacks)
+		for err == nil && len(result.Pending) > 0 && !migrateDryRun && term.IsTerminal(int(os.Stdin.Fd())) {
+			for _, code := range result.Pending {
+				prompt := promptui.Select{
+					Label: fmt.Sprintf("Tech stack '%s' holds", code),
+					Items: []string{"frontends", "backends"},
+				}
+				_, side, perr := prompt.Run()
+				if perr != nil {
+					color.Yellow("Migration cancelled\n")
+					return
+				}
+				stacks[code] = side
+			}
+			result, err = config.Default().MigrateSchema(false, stacks)
+		}
+		if err != nil {
+			color.Red("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(result.Pending) > 0 {
+			color.Yellow("⚠️  Cannot tell whether tech stacks %s hold frontends or backends; the config file was not changed\n",
+				strings.Join(result.Pending, ", "))
+			fmt.Printf("Place them with --stack, e.g. dir-init config migrate --stack %s=frontend\n", result.Pending[0])
+			os.Exit(1)
+		}
+
+		if len(result.Changes) == 0 {
+			color.Green("✓ Config file is at version %d, nothing to migrate\n", config.CurrentVersion)
+			return
+		}
+
+		if migrateDryRun {
+			color.Yellow("Migrating %s from version %d to %d would:\n", config.GetConfigPath(), result.From, result.To)
+		} else {
+			color.Green("✓ Migrated %s from version %d to %d:\n", config.GetConfigPath(), result.From, result.To)
+		}
+		for _, change := range result.Changes {
+			fmt.Printf("  • %s\n", change)
+		}
+		if result.Backup != "" {
+			fmt.Printf("\nBackup of the old file: %s\n", result.Backup)
+		}
+	},
+}
+
+// moveLegacyFiles moves the files of an older ~/.dir-init directory to their
+// XDG locations, printing each move
+func moveLegacyFiles() error {
+	moved, err := config.Default().Migrate()
+	for _, m := range moved {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Moved %s\n", m)
+	}
+	return err
+}
+
+// initConfigFile creates the user config file for the commands that work on
+// the file itself, moving an older one from ~/.dir-init or writing the defaults
+func initConfigFile() error {
+	if err := moveLegacyFiles(); err != nil {
+		return err
+	}
+	return config.InitConfig()
+}
+
 var configEditCmd = &cobra.Command{
 	Use:   "edit",
 	Short: "Edit config in default editor",
 	Run: func(cmd *cobra.Command, args []string) {
+		if err := initConfigFile(); err != nil {
+			color.Red("❌ Error: %v\n", err)
+			return
+		}
 		configPath := config.GetConfigPath()
 
 		// Get editor from environment or use default
@@ -161,9 +518,10 @@ var configAddCmd = &cobra.Command{
 }
 
 var configAddTechStackCmd = &cobra.Command{
-	Use:   "techstack <code> <description>",
-	Short: "Add a tech stack",
-	Args:  cobra.ExactArgs(2),
+	Use:        "techstack <code> <description>",
+	Short:      "Add a tech stack",
+	Deprecated: "tech stacks are replaced by frontends and backends; add those with 'dir-init config edit'",
+	Args:       cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		code := args[0]
 		description := args[1]
@@ -178,9 +536,10 @@ var configAddTechStackCmd = &cobra.Command{
 }
 
 var configAddFrameworkCmd = &cobra.Command{
-	Use:   "framework <techstack> <code> <description>",
-	Short: "Add a framework",
-	Args:  cobra.ExactArgs(3),
+	Use:        "framework <techstack> <code> <description>",
+	Short:      "Add a framework",
+	Deprecated: "frameworks are replaced by frontends and backends; add those with 'dir-init config edit'",
+	Args:       cobra.ExactArgs(3),
 	Run: func(cmd *cobra.Command, args []string) {
 		techStack := args[0]
 		code := args[1]
@@ -225,7 +584,7 @@ var configRemoveTechStackCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		code := args[0]
 
-		cfg, err := config.LoadConfig()
+		cfg, err := config.LoadUserConfig()
 		if err != nil {
 			color.Red("❌ Error loading config: %v\n", err)
 			return
@@ -265,7 +624,7 @@ var configRemoveFrameworkCmd = &cobra.Command{
 		techStack := args[0]
 		code := args[1]
 
-		cfg, err := config.LoadConfig()
+		cfg, err := config.LoadUserConfig()
 		if err != nil {
 			color.Red("❌ Error loading config: %v\n", err)
 			return
@@ -311,22 +670,29 @@ var configRemoveWordCmd = &cobra.Command{
 		category := args[0]
 		word := args[1]
 
-		cfg, err := config.LoadConfig()
+		cfg, err := config.LoadUserConfig()
 		if err != nil {
 			color.Red("❌ Error loading config: %v\n", err)
 			return
 		}
 
 		// Check if category exists
-		if cfg.Categories[category] == nil {
+		cat, exists := cfg.Categories[category]
+		if !exists {
 			color.Yellow("⚠️  Category '%s' not found\n", category)
 			return
 		}
 
+		// Words of word list files are only removed by editing the file
+		if file := cat.WordFile(word); file != "" {
+			color.Yellow("⚠️  Word '%s' comes from %s; remove it from that file\n", word, file)
+			return
+		}
+
 		// Find and remove word
 		found := false
 		newWords := []string{}
-		for _, w := range cfg.Categories[category] {
+		for _, w := range cat.Words {
 			if w != word {
 				newWords = append(newWords, w)
 			} else {
@@ -339,7 +705,9 @@ var configRemoveWordCmd = &cobra.Command{
 			return
 		}
 
-		cfg.Categories[category] = newWords
+		cat.Words = newWords
+		delete(cat.WordWeights, word)
+		cfg.Categories[category] = cat
 		if err := config.SaveConfig(cfg); err != nil {
 			color.Red("❌ Error saving config: %v\n", err)
 			return
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aravindcm49/dir-init/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// testCommand is a root command with some of the persistent flags of rootCmd
+// and a subcommand with a flag of its own
+func testCommand() *cobra.Command {
+	root := &cobra.Command{Use: "dir-init"}
+	root.PersistentFlags().String("case", "", "")
+	root.PersistentFlags().Int("max-length", 0, "")
+	root.PersistentFlags().Bool("emoji", false, "")
+	root.PersistentFlags().Bool("no-numbers", false, "")
+	root.PersistentFlags().Bool("no-sfw", false, "")
+	root.PersistentFlags().String("word-match", "", "")
+	root.PersistentFlags().String("config", "", "")
+
+	show := &cobra.Command{Use: "show", Run: func(*cobra.Command, []string) {}}
+	show.Flags().Bool("origin", false, "")
+	root.AddCommand(show)
+	return show
+}
+
+func TestFlagLayers(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantSources []string
+		want        func(c *config.LayeredConfig) bool
+	}{
+		{
+			name: "no flags",
+			args: []string{"--origin"},
+			want: func(c *config.LayeredConfig) bool { return c.Case == "kebab" },
+		},
+		{
+			name:        "settings",
+			args:        []string{"--case", "snake", "--max-length=20"},
+			wantSources: []string{"--case", "--max-length"},
+			want:        func(c *config.LayeredConfig) bool { return c.Case == "snake" && c.MaxLength == 20 },
+		},
+		{
+			name:        "flags named differently",
+			args:        []string{"--emoji", "--no-numbers", "--no-sfw=false"},
+			wantSources: []string{"--emoji", "--no-numbers", "--no-sfw"},
+			want: func(c *config.LayeredConfig) bool {
+				return c.Emojis && !*c.IncludeNumbers && *c.SafeForWork
+			},
+		},
+		{
+			name:        "flags that are not settings",
+			args:        []string{"--word-match", "^p", "--config", "other.yaml"},
+			wantSources: []string{"--word-match=^p (not a config setting)"},
+			want:        func(c *config.LayeredConfig) bool { return c.Case == "kebab" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(path, []byte("case: kebab\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			t.Chdir(dir)
+
+			cmd := testCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			layers := flagLayers(cmd)
+
+			var sources []string
+			for _, l := range layers {
+				sources = append(sources, l.Source)
+			}
+			if !reflect.DeepEqual(sources, tt.wantSources) {
+				t.Errorf("flag layers = %q, want %q", sources, tt.wantSources)
+			}
+
+			merged, err := config.NewStore(config.FilePaths(path)).LoadLayered(layers...)
+			if err != nil {
+				t.Fatalf("LoadLayered: %v", err)
+			}
+			if !tt.want(merged) {
+				t.Errorf("merged config does not reflect %v: %+v", tt.args, *merged.Config)
+			}
+		})
+	}
+}
@@ -1,9 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -23,11 +24,11 @@ func init() {
 var examplesCmd = &cobra.Command{
 	Use:   "examples [flags]",
 	Short: "Show example folder names",
-	Long: `Show example folder names for demonstration.
+	Long: `Show example folder names generated from the categories of the config.
 
 Examples:
   dir-init examples
-  dir-init examples -c tech -n 5
+  dir-init examples -c dev -n 5
   dir-init examples -c silly`,
 	Run: func(cmd *cobra.Command, args []string) {
 		printExamples()
@@ -35,97 +36,54 @@ Examples:
 }
 
 func printExamples() {
-	fmt.Println()
-	color.Cyan("Example Folder Names:\n")
-
-	if exampleCategory != "" {
-		printCategoryExamples(exampleCategory, exampleCount)
-	} else {
-		printAllExamples(exampleCount)
+	cfg, err := dirinit.LoadConfig()
+	if err != nil {
+		color.Red("❌ Error loading config: %v\n", err)
+		return
 	}
 
 	fmt.Println()
-	color.Green("Try 'dir-init generate -c <category>' to create your own names!")
-}
-
-func printCategoryExamples(category string, count int) {
-	color.Yellow("Category: %s\n", category)
-
-	examples := getCategoryExamples(category)
+	color.Cyan("Example Folder Names:\n")
 
-	for i := 0; i < min(count, len(examples)); i++ {
-		fmt.Printf("• %s\n", examples[i])
+	categories := dirinit.Categories(cfg)
+	if exampleCategory != "" {
+		if _, ok := cfg.Categories[exampleCategory]; !ok && exampleCategory != "all" {
+			color.Red("❌ Unknown category '%s' (see 'dir-init categories')\n", exampleCategory)
+			return
+		}
+		categories = []string{exampleCategory}
 	}
-}
-
-func printAllExamples(count int) {
-	categories := []string{"tech", "food", "animals", "pop", "silly", "dev"}
 
 	for _, category := range categories {
-		color.Yellow("%s:\n", strings.Title(category))
-		examples := getCategoryExamples(category)
-
-		for i := 0; i < min(min(count, len(examples)), 2); i++ {
-			fmt.Printf("  • %s\n", examples[i])
+		names, err := exampleNames(cfg, category, exampleCount)
+		if err != nil {
+			color.Red("❌ %v\n", err)
+			return
 		}
-		if len(examples) > 2 {
-			fmt.Printf("  • ...and %d more\n", len(examples)-2)
+
+		color.Yellow("%s:\n", category)
+		for _, name := range names {
+			fmt.Printf("  • %s\n", name)
 		}
 		fmt.Println()
 	}
-}
 
-func getCategoryExamples(category string) []string {
-	examples := map[string][]string{
-		"tech": {
-			"code-1234",
-			"debug-abc2",
-			"api-v2beta",
-			"service-x42",
-			"binary-test",
-		},
-		"food": {
-			"pizza-fresh",
-			"burger-delicious",
-			"taco-hot",
-			"pasta-cold",
-			"donut-sweet",
-		},
-		"animals": {
-			"penguin-cute",
-			"koala-gentle",
-			"dolphin-smart",
-			"eagle-mighty",
-			"turtle-slow",
-		},
-		"pop": {
-			"ninja-epic",
-			"wizard-magical",
-			"knight-brave",
-			"astronaut-space",
-			"robot-future",
-		},
-		"silly": {
-			"potato-silly",
-			"banana-goofy",
-			"unicorn-odd",
-			"noodle-weird",
-			"pickle-strange",
-		},
-		"dev": {
-			"github-v1",
-			"docker-prod",
-			"react-ui",
-			"python-dev",
-			"k8s-cluster",
-		},
-	}
+	color.Green("Try 'dir-init generate -c <category>' to create your own names!")
+}
 
-	if ex, exists := examples[category]; exists {
-		return ex
+// exampleNames generates count names from a category without touching the disk
+func exampleNames(cfg *dirinit.Config, category string, count int) ([]string, error) {
+	opts := append(globalOptions(),
+		dirinit.WithConfig(cfg),
+		dirinit.WithCategory(category),
+		dirinit.WithCount(count),
+		dirinit.WithoutDiskCheck(),
+	)
+	gen, err := dirinit.New(opts...)
+	if err != nil {
+		return nil, err
 	}
-
-	return []string{"example-1", "example-2", "example-3"}
+	return gen.Generate(context.Background())
 }
 
 func min(a, b int) int {
@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/aravindcm49/dir-init/internal/generator"
+	"github.com/aravindcm49/dir-init/internal/session"
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -15,19 +21,31 @@ var (
 	suffixType   string
 	suffixLength int
 	count        int
-	seed         int64
 	outputFormat string
+	layoutFlag   string
+	frontendFlag string
+	backendFlag  string
+	targetDir    string
+	samplingFlag string
+	composeFlag  string
+	wordsFrom    string
 )
 
 func init() {
 	rootCmd.AddCommand(generateCmd)
 
-	generateCmd.Flags().StringVarP(&category, "category", "c", "all", "Category to use (tech, food, animals, pop, silly, dev, all)")
-	generateCmd.Flags().StringVarP(&suffixType, "suffix", "s", "mixed", "Suffix type (alpha, numeric, mixed, timestamp)")
-	generateCmd.Flags().IntVarP(&suffixLength, "length", "l", 4, "Suffix length (1-8)")
+	generateCmd.Flags().StringVarP(&category, "category", "c", "all", "Categories to use: a name, all, a union such as food,animals or exclusions such as all,!dev")
+	generateCmd.Flags().StringVarP(&suffixType, "suffix", "s", "mixed", "Suffix type (alpha, numeric, mixed, timestamp, ulid, uuid7, date, datetime, hex, base32, sequence, pronounceable)")
+	generateCmd.Flags().IntVarP(&suffixLength, "length", "l", 4, "Suffix length (range depends on the suffix type; longer types default to their minimum)")
 	generateCmd.Flags().IntVarP(&count, "count", "n", 1, "Number of names to generate")
-	generateCmd.Flags().Int64VarP(&seed, "seed", "S", 0, "Random seed for reproducible results")
-	generateCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format (text, json)")
+	generateCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format (text, json, ndjson)")
+	generateCmd.Flags().StringVar(&samplingFlag, "sampling", "", "Sampling strategy (uniform-category, uniform-word, weighted; overrides config)")
+	generateCmd.Flags().StringVar(&composeFlag, "compose", "", "Compose words by part of speech, e.g. adj-noun or adj-adj-noun (overrides config)")
+	generateCmd.Flags().StringVar(&layoutFlag, "layout", "", "Name layout, e.g. \"{frontend}-{backend}-{word}{suffix}\" (overrides config)")
+	generateCmd.Flags().StringVar(&frontendFlag, "frontend", "", "Frontend code for the {frontend} layout token")
+	generateCmd.Flags().StringVarP(&targetDir, "dir", "d", ".", "Directory checked for existing names")
+	generateCmd.Flags().StringVar(&backendFlag, "backend", "", "Backend code for the {backend} layout token")
+	generateCmd.Flags().StringVar(&wordsFrom, "words-from", "", "Use the words of a file, one per line, for this run (- reads stdin)")
 }
 
 var generateCmd = &cobra.Command{
@@ -36,52 +54,161 @@ var generateCmd = &cobra.Command{
 	Long: `Generate funny folder names with specified categories and options.
 
 Examples:
-  dir-init generate -c tech
+  dir-init generate -c dev
   dir-init generate -c food -n 5
+  dir-init generate -c food,animals -n 5
+  dir-init generate -c 'all,!dev' -n 5
+  dir-init generate -c animals --word-match '^p'
+  dir-init generate --words-from team-names.txt -n 5
+  printf 'alpha\nbravo\n' | dir-init generate --words-from - -n 2
   dir-init generate -c silly -s numeric -l 6
-  dir-init generate -c all -n 10 -o json`,
-	Run: func(cmd *cobra.Command, args []string) {
-		config := generator.DefaultConfig()
-		config.Category = category
-		config.Count = count
-		config.Seed = seed
+  dir-init generate -c all -n 10 -o json
+  dir-init generate -n 50000 -o ndjson > fixtures.ndjson
+  dir-init generate -c animals --compose adj-noun
+  dir-init generate -c food --case snake
+  dir-init generate --frontend rct --backend rails --layout "{frontend}-{backend}-{word}{suffix}" --alliterate
+  dir-init generate --frontend rct --backend go --layout "{date:0102}_{backend}_{word}{suffix:alpha:5}"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// errors past this point are not about the usage of the flags
+		cmd.SilenceUsage = true
+
+		cfg, err := dirinit.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
 
 		// Parse suffix type
-		switch strings.ToLower(suffixType) {
-		case "alpha":
-			config.SuffixType = generator.SuffixAlpha
-		case "numeric":
-			config.SuffixType = generator.SuffixNumeric
-		case "mixed":
-			config.SuffixType = generator.SuffixMixed
-		case "timestamp":
-			config.SuffixType = generator.SuffixTimestamp
-		default:
-			fmt.Printf("Invalid suffix type: %s. Using default.\n", suffixType)
+		spec, err := dirinit.ParseSuffix(suffixType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid suffix type: %s. Using default.\n", suffixType)
+			spec, _ = dirinit.ParseSuffix("mixed")
 		}
 
-		// Validate suffix length
-		if suffixLength < 1 || suffixLength > 8 {
-			fmt.Printf("Suffix length must be between 1 and 8. Using default 4.\n")
-			config.SuffixLength = 4
-		} else {
-			config.SuffixLength = suffixLength
+		// Validate suffix length against the rules of the suffix type
+		length := spec.DefaultLength()
+		if cmd.Flags().Changed("length") {
+			length = min(max(suffixLength, spec.MinLength), spec.MaxLength)
+			if length != suffixLength {
+				fmt.Fprintf(os.Stderr, "Suffix length for %s must be between %d and %d. Using %d.\n",
+					spec.Type, spec.MinLength, spec.MaxLength, length)
+			}
 		}
 
-		// Create generator and generate names
-		generator := generator.NewGenerator(config)
-		names := generator.Generate()
+		opts := globalOptions()
+		selected := category
+		if wordsFrom != "" {
+			words, err := dirinit.ReadWordFile(wordsFrom)
+			if err != nil {
+				return fmt.Errorf("failed to read word list:\n%w", err)
+			}
+			// The list is a category of its own, combined with -c when given
+			name := wordListCategory(wordsFrom)
+			opts = append(opts, dirinit.WithWordList(name, words))
+			selected = name
+			if cmd.Flags().Changed("category") {
+				selected = category + "," + name
+			}
+		}
+
+		gen, err := dirinit.New(append(opts,
+			dirinit.WithConfig(cfg),
+			dirinit.WithCategory(selected),
+			dirinit.WithSuffix(string(spec.Type), length),
+			dirinit.WithCount(count),
+			dirinit.WithSeed(seed),
+			dirinit.WithLayout(layoutFlag),
+			dirinit.WithFrontend(frontendFlag),
+			dirinit.WithBackend(backendFlag),
+			dirinit.WithTargetDir(targetDir),
+			dirinit.WithNow(time.Now()),
+			dirinit.WithSampling(samplingFlag),
+			dirinit.WithComposition(composeFlag),
+		)...)
+		if err != nil {
+			return err
+		}
+
+		format := strings.ToLower(outputFormat)
+		if format == "ndjson" {
+			// Streamed names are not kept, so the session records the seed and selections only
+			err := outputNDJSON(cmd.Context(), gen)
+			reportRejections(verboseWriter(os.Stderr), gen.Rejections())
+			recordSession(verboseWriter(os.Stderr), newSession("generate", gen, nil))
+			if err != nil {
+				return fmt.Errorf("failed to generate names: %w", err)
+			}
+			return nil
+		}
+
+		names, genErr := gen.Generate(cmd.Context())
+		if genErr != nil && len(names) == 0 {
+			return fmt.Errorf("failed to generate names: %w", genErr)
+		}
+
+		reportRejections(verboseWriter(os.Stderr), gen.Rejections())
+		recordSession(verboseWriter(os.Stderr), newSession("generate", gen, names))
 
 		// Output results
-		switch strings.ToLower(outputFormat) {
+		switch format {
 		case "json":
 			outputJSON(names)
 		default:
 			outputText(names)
 		}
+
+		// the names that were found are printed, but fewer than asked for is a failure
+		if genErr != nil {
+			return fmt.Errorf("failed to generate names: %w", genErr)
+		}
+		return nil
 	},
 }
 
+// wordListCategory names the category of a --words-from list after its file,
+// such as "team-names" for team-names.txt, or "stdin" for -
+func wordListCategory(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// newSession records the effective settings of a run so it can be replayed
+func newSession(command string, gen *dirinit.Generator, names []string) *session.Session {
+	s := gen.Settings()
+	return &session.Session{
+		Command:      command,
+		Seed:         s.Seed,
+		Time:         s.Now,
+		Layout:       s.Layout,
+		Frontend:     s.Frontend,
+		Backend:      s.Backend,
+		Category:     s.Category,
+		Word:         s.Word,
+		Sampling:     s.Sampling,
+		Compose:      s.Compose,
+		Case:         s.Case,
+		Separator:    s.Separator,
+		MaxLength:    s.MaxLength,
+		MaxWord:      s.MaxWordLength,
+		MaxCode:      s.MaxCodeLength,
+		NoSFW:        !s.SafeForWork,
+		SuffixDigits: s.SuffixDigits,
+		Emojis:       s.Emojis,
+		NoNumbers:    !s.IncludeNumbers,
+		Portability:  s.Portability,
+		Alliterate:   s.Alliterate,
+		WordMatch:    s.WordMatch,
+		WordLists:    s.WordLists,
+		SuffixType:   s.SuffixType,
+		SuffixLength: s.SuffixLength,
+		Count:        s.Count,
+		Reserved:     gen.Reserved(),
+		Sequences:    gen.SequenceStarts(),
+		Names:        names,
+	}
+}
+
 func outputText(names []string) {
 	fmt.Println()
 	if len(names) == 1 {
@@ -113,4 +240,24 @@ func outputJSON(names []string) {
 	}
 
 	fmt.Println(string(jsonData))
+}
+
+// outputNDJSON writes one JSON object per line as names are generated, so
+// large batches never have to fit in memory
+func outputNDJSON(ctx context.Context, gen *dirinit.Generator) error {
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	enc := json.NewEncoder(w)
+	for name, err := range gen.Names(ctx) {
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(struct {
+			Name string `json:"name"`
+		}{name}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
\ No newline at end of file
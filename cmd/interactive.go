@@ -2,19 +2,24 @@ package cmd
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aravindcm49/dir-init/cmd/tui/models"
 	"github.com/aravindcm49/dir-init/internal/config"
-	"github.com/aravindcm49/dir-init/internal/generator"
+	"github.com/aravindcm49/dir-init/internal/utils"
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fatih/color" // This line is kept as removing it would cause compilation errors due to its usage later in the file.
 	"golang.org/x/term"
 )
 
-func interactive(verbose bool) {
+func interactive(ctx context.Context, verbose bool, seed int64) {
 	green := color.New(color.FgGreen).Add(color.Bold)
 	yellow := color.New(color.FgYellow).Add(color.Bold)
 
@@ -22,6 +27,23 @@ func interactive(verbose bool) {
 		fmt.Println("[verbose] Starting interactive mode")
 	}
 
+	// Validate the naming layout before asking any questions
+	cfg, err := dirinit.LoadConfig()
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		return
+	}
+
+	layout := cfg.Layout
+	if layout == "" {
+		layout = dirinit.EnhancedLayout
+	}
+
+	if _, err := dirinit.New(append(globalOptions(), dirinit.WithConfig(cfg), dirinit.WithLayout(layout))...); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
 	// Clear screen once at start
 	// fmt.Print("\033[H\033[2J")
 
@@ -104,18 +126,12 @@ func interactive(verbose bool) {
 	// Step 3: Category Selection
 	yellow.Printf("Step 3/4: Select Category\n")
 
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		fmt.Printf("Error loading config: %v\n", err)
-		return
-	}
-
 	categoryItems := []models.Item{}
-	for cat := range cfg.Categories {
-		// Capitalize first letter for description
-		desc := cat
-		if len(cat) > 0 {
-			desc = strings.ToUpper(cat[:1]) + cat[1:]
+	for _, cat := range dirinit.Categories(cfg) {
+		category := cfg.Categories[cat]
+		desc := categoryDescription(cat, category.Description)
+		if examples := categoryExamples(category.Examples, category.Words, 3); len(examples) > 0 {
+			desc += " (" + strings.Join(examples, ", ") + ")"
 		}
 		categoryItems = append(categoryItems, models.Item{
 			Code:        cat,
@@ -125,7 +141,7 @@ func interactive(verbose bool) {
 	// Add "all" option
 	categoryItems = append(categoryItems, models.Item{Code: "all", Description: "All Categories"})
 
-	categoryModel := models.NewSelector("", categoryItems)
+	categoryModel := models.NewMultiSelector("", categoryItems)
 	p = tea.NewProgram(categoryModel)
 	finalModel, err = p.Run()
 	if err != nil {
@@ -136,6 +152,12 @@ func interactive(verbose bool) {
 	cm := finalModel.(models.SelectorModel)
 	selectedCat := cm.GetSelected()
 
+	// Marked categories build an expression such as "food,animals" or "all,!dev"
+	include, exclude := cm.Marked()
+	if len(include) > 0 || len(exclude) > 0 {
+		selectedCat = &models.Item{Code: categoryExpression(include, exclude)}
+	}
+
 	if selectedCat == nil {
 		return
 	}
@@ -146,12 +168,10 @@ func interactive(verbose bool) {
 	}
 
 	var customWord string
-	var useCustomWord bool
 
 	// Handle custom word if needed
 	if selectedCat.IsCustom {
 		customWord = selectedCat.Code
-		useCustomWord = true
 		selectedCategory = "food" // Default
 
 		if cm.ShouldSave() {
@@ -165,14 +185,22 @@ func interactive(verbose bool) {
 	fmt.Print("\033[A\033[K") // Move up and clear line
 	fmt.Printf("Step 3/4: Select Category >> %s\n", selectedCategory)
 
+	// Catch an expression that selects no words before asking for the suffix
+	if _, err := dirinit.New(append(globalOptions(), dirinit.WithConfig(cfg), dirinit.WithLayout(layout),
+		dirinit.WithCategory(selectedCategory), dirinit.WithWord(customWord))...); err != nil {
+		color.Red("❌ %v\n", err)
+		return
+	}
+
 	// Step 4: Suffix Type Selection
 	yellow.Printf("Step 4/4: Select Suffix Type\n")
 
-	suffixItems := []models.Item{
-		{Code: "alphabetic", Description: "Alphabetic (abc)"},
-		{Code: "numeric", Description: "Numeric (123)"},
-		{Code: "mixed", Description: "Mixed (a1b2)"},
-		{Code: "timestamp", Description: "Timestamp"},
+	suffixItems := []models.Item{}
+	for _, spec := range dirinit.SuffixTypes() {
+		suffixItems = append(suffixItems, models.Item{
+			Code:        string(spec.Type),
+			Description: fmt.Sprintf("%s (%s)", spec.Description, spec.Example),
+		})
 	}
 
 	suffixModel := models.NewSelector("", suffixItems)
@@ -190,16 +218,10 @@ func interactive(verbose bool) {
 		return
 	}
 
-	var suffixType generator.SuffixType
-	switch selectedSuf.Code {
-	case "alphabetic":
-		suffixType = generator.SuffixAlpha
-	case "numeric":
-		suffixType = generator.SuffixNumeric
-	case "mixed":
-		suffixType = generator.SuffixMixed
-	case "timestamp":
-		suffixType = generator.SuffixTimestamp
+	suffixSpec, err := dirinit.ParseSuffix(selectedSuf.Code)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
 	}
 
 	if verbose {
@@ -213,40 +235,44 @@ func interactive(verbose bool) {
 	grey := color.New(color.FgHiBlack)
 
 	// Arrow key controlled count input
-	fmt.Printf("\rHow many directories to create? %s", grey.Sprint("<use ↑ to increase, ↓ to decrease, Enter to confirm>: "))
-	count := readArrowCount(1, 10)
+	fmt.Printf("\rHow many directories to create? %s", grey.Sprint("<type a number or use ↑ to increase, ↓ to decrease, Enter to confirm>: "))
+	count := readArrowCount(1)
 	fmt.Printf("\rHow many directories to create? %d\n", count)
 
 	if verbose {
 		fmt.Printf("[verbose] Selected count: %d\n", count)
 	}
 
+	gen, err := dirinit.New(append(globalOptions(),
+		dirinit.WithConfig(cfg),
+		dirinit.WithLayout(layout),
+		dirinit.WithSeed(seed),
+		dirinit.WithNow(time.Now()),
+		dirinit.WithFrontend(selectedFrontendCode),
+		dirinit.WithBackend(selectedBackendCode),
+		dirinit.WithCategory(selectedCategory),
+		dirinit.WithWord(customWord),
+		dirinit.WithSuffix(string(suffixSpec.Type), suffixSpec.DefaultLength()),
+		dirinit.WithCount(count),
+	)...)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
 	// Generate and create directories
-	// fmt.Println() // Single newline before output
+	var names []string
 	for i := 0; i < count; i++ {
-		var name string
-		var err error
-
-		if useCustomWord {
-			name = fmt.Sprintf("%s-%s-%s-%s", selectedFrontendCode, selectedBackendCode, customWord, generateSimpleSuffix(suffixType))
-		} else {
-			cfg, _ := config.LoadConfig()
-			genConfig := generator.DefaultConfig()
-			genConfig.Categories = cfg.Categories
-
-			gen := generator.NewGenerator(genConfig)
-			name, err = gen.GenerateEnhancedName(selectedFrontendCode, selectedBackendCode, selectedCategory, suffixType, 4)
-			if err != nil {
-				fmt.Printf("Error generating name: %v\n", err)
-				continue
-			}
+		name, err := gen.CreateNext(ctx)
+		if errors.Is(err, dirinit.ErrNameSpaceExhausted) || errors.Is(err, context.Canceled) {
+			fmt.Printf("❌ %v\n", err)
+			break
 		}
-
-		err = os.MkdirAll(name, 0755)
 		if err != nil {
-			fmt.Printf("❌ Failed to create directory '%s': %v\n", name, err)
+			fmt.Printf("❌ %v\n", err)
 			continue
 		}
+		names = append(names, name)
 
 		if verbose {
 			fmt.Printf("[verbose] Created directory: %s\n", name)
@@ -254,6 +280,9 @@ func interactive(verbose bool) {
 
 		green.Printf("%s created!\n", name)
 	}
+
+	reportRejections(verboseWriter(os.Stdout), gen.Rejections())
+	recordSession(verboseWriter(os.Stdout), newSession("interactive", gen, names))
 }
 
 func buildFrontendItems() []models.Item {
@@ -296,21 +325,24 @@ func buildBackendItems() []models.Item {
 	return items
 }
 
-func generateSimpleSuffix(suffixType generator.SuffixType) string {
-	switch suffixType {
-	case generator.SuffixAlpha:
-		return "abcd"
-	case generator.SuffixNumeric:
-		return "1234"
-	case generator.SuffixTimestamp:
-		return fmt.Sprintf("%d", os.Getpid()%10000)
-	default:
-		return "a1b2"
+// categoryExpression joins marked categories into a category expression.
+// Excluding "all" has no meaning, so that mark is ignored.
+func categoryExpression(include, exclude []string) string {
+	terms := include
+	for _, cat := range exclude {
+		if cat != "all" {
+			terms = append(terms, "!"+cat)
+		}
 	}
+	if len(terms) == 0 {
+		return "all"
+	}
+	return strings.Join(terms, ",")
 }
 
-// readArrowCount reads arrow keys to increment/decrement a count value
-func readArrowCount(min, max int) int {
+// readArrowCount reads arrow keys to increment/decrement a count value, or digits to type it.
+// The count stays at most utils.MaxDirectoryCount, the limit --count is checked against.
+func readArrowCount(min int) int {
 	// Save current terminal settings
 	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
 	if err != nil {
@@ -321,6 +353,7 @@ func readArrowCount(min, max int) int {
 
 	scanner := bufio.NewReader(os.Stdin)
 	count := min
+	typed := "" // digits typed since the last arrow key
 
 	for {
 		// Read first byte
@@ -337,18 +370,29 @@ func readArrowCount(min, max int) int {
 
 			// \x1b[A = Up, \x1b[B = Down
 			if b2 == 91 && b3 == 65 { // Up
-				if count < max {
+				if count < utils.MaxDirectoryCount {
 					count++
 				}
-				fmt.Printf("\r\x1b[KHow many directories to create? %d  ", count)
 			} else if b2 == 91 && b3 == 66 { // Down
 				if count > min {
 					count--
 				}
-				fmt.Printf("\r\x1b[KHow many directories to create? %d  ", count)
 			}
+			typed = ""
+		} else if b >= '0' && b <= '9' && len(typed) < 9 {
+			typed += string(b)
+			count, _ = strconv.Atoi(typed)
+			if count > utils.MaxDirectoryCount {
+				count = utils.MaxDirectoryCount
+			}
+		} else if (b == 127 || b == 8) && typed != "" { // Backspace
+			typed = typed[:len(typed)-1]
+			count, _ = strconv.Atoi(typed)
 		} else if b == 13 || b == 10 { // Enter key
-			return count
+			return max(count, min)
+		} else {
+			continue
 		}
+		fmt.Printf("\r\x1b[KHow many directories to create? %d  ", count)
 	}
 }
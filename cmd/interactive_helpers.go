@@ -129,10 +129,7 @@ func promptCustomWord() (word string, save bool, err error) {
 			if len(input) < 2 || len(input) > 20 {
 				return fmt.Errorf("word must be 2-20 characters")
 			}
-			if !regexp.MustCompile(`^[a-z0-9]+$`).MatchString(input) {
-				return fmt.Errorf("word must be lowercase alphanumeric (no spaces or special chars)")
-			}
-			return nil
+			return config.ValidateWord(input)
 		},
 	}
 
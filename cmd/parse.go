@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	parseLayoutFlag   string
+	parseSuffixType   string
+	parseOutputFormat string
+)
+
+var parseCmd = &cobra.Command{
+	Use:   "parse <name>...",
+	Short: "Split generated names back into their components",
+	Long: `Split generated names into frontend, backend, word, category and suffix.
+
+Codes and words are looked up in the loaded config, so words containing
+hyphens such as "cherry-pick" are recovered whole. Without --layout the
+layout of the config file is used, or else the interactive layout
+({frontend}-{backend}-{word}{suffix}) and the generate layout ({word}{suffix})
+are tried in turn.
+
+Examples:
+  dir-init parse rct-node-pizza-a1b2
+  dir-init parse rct-node-cherry-pick-x9k2 pizza-a1b2 -o json
+  dir-init parse 0102_go_pizza-kqzvb --layout "{date:0102}_{backend}_{word}{suffix:alpha:5}"
+
+Every name is printed, and the command exits with status 1 when any of them
+does not match.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Errors from here on are not usage errors
+		cmd.SilenceUsage = true
+
+		cfg, err := dirinit.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		layouts := []string{parseLayoutFlag}
+		if parseLayoutFlag == "" && cfg.Layout == "" {
+			layouts = []string{dirinit.EnhancedLayout, dirinit.DefaultLayout}
+		}
+
+		var gens []*dirinit.Generator
+		for _, layout := range layouts {
+			opts := append(globalOptions(), dirinit.WithConfig(cfg), dirinit.WithLayout(layout))
+			if parseSuffixType != "" {
+				opts = append(opts, dirinit.WithSuffix(parseSuffixType, 0))
+			}
+			gen, err := dirinit.New(opts...)
+			if err != nil {
+				return err
+			}
+			gens = append(gens, gen)
+		}
+
+		results := make([]parseResult, 0, len(args))
+		failed := 0
+		for _, name := range args {
+			r := parseName(gens, name)
+			if r.err != nil {
+				failed++
+			}
+			results = append(results, r)
+		}
+
+		if parseOutputFormat == "json" {
+			if err := outputParseJSON(results, cfg); err != nil {
+				return err
+			}
+		} else {
+			outputParseText(results, cfg)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d names could not be parsed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(parseCmd)
+
+	parseCmd.Flags().StringVar(&parseLayoutFlag, "layout", "", "Name layout the names were generated with (overrides config)")
+	parseCmd.Flags().StringVarP(&parseSuffixType, "suffix", "s", "", "Suffix type to prefer when a suffix fits several types")
+	parseCmd.Flags().StringVarP(&parseOutputFormat, "output", "o", "text", "Output format (text, json)")
+}
+
+// parseResult is a parsed name, or the error of the last layout tried
+type parseResult struct {
+	parsed dirinit.Parsed
+	name   string
+	err    error
+}
+
+// parseName parses name with the first generator whose layout it matches.
+// Paths such as "projects/pizza-a1b2/" are reduced to the directory name.
+func parseName(gens []*dirinit.Generator, name string) parseResult {
+	name = filepath.Base(filepath.Clean(name))
+
+	var err error
+	for _, gen := range gens {
+		var parsed dirinit.Parsed
+		if parsed, err = gen.Parse(name); err == nil {
+			return parseResult{parsed: parsed, name: name}
+		}
+	}
+	return parseResult{name: name, err: err}
+}
+
+// codeDescription returns the description of a frontend or backend code in the config
+func codeDescription(cfg *dirinit.Config, code string, backend bool) string {
+	if backend {
+		for _, be := range cfg.Backends {
+			if strings.EqualFold(be.Code, code) {
+				return be.Description
+			}
+		}
+		return ""
+	}
+	for _, fe := range cfg.Frontends {
+		if strings.EqualFold(fe.Code, code) {
+			return fe.Description
+		}
+	}
+	return ""
+}
+
+func outputParseText(results []parseResult, cfg *dirinit.Config) {
+	for _, r := range results {
+		fmt.Println()
+		if r.err != nil {
+			color.Red("❌ %v\n", r.err)
+			continue
+		}
+
+		p := r.parsed
+		color.Cyan("%s\n", p.Name)
+		field := func(label, value string) {
+			if value != "" {
+				fmt.Printf("  %-10s %s\n", label+":", value)
+			}
+		}
+		withDescription := func(code, desc string) string {
+			if code == "" || desc == "" {
+				return code
+			}
+			return fmt.Sprintf("%s (%s)", code, desc)
+		}
+
+		field("layout", p.Layout)
+		field("emoji", p.Emoji)
+		field("frontend", withDescription(p.Frontend, codeDescription(cfg, p.Frontend, false)))
+		field("backend", withDescription(p.Backend, codeDescription(cfg, p.Backend, true)))
+		field("word", p.Word)
+		switch {
+		case len(p.Categories) > 1:
+			field("category", fmt.Sprintf("%s (also in %s)", p.Category, strings.Join(p.Categories[1:], ", ")))
+		case p.Category != "":
+			field("category", p.Category)
+		case p.Word != "":
+			field("category", "unknown (custom word)")
+		}
+		field("date", p.Date)
+		for _, s := range p.Suffixes {
+			value := fmt.Sprintf("%s (%s)", s.Text, s.Type)
+			if others := otherSuffixTypes(s); len(others) > 0 {
+				value = fmt.Sprintf("%s (%s; also fits %s)", s.Text, s.Type, strings.Join(others, ", "))
+			}
+			field("suffix", value)
+		}
+	}
+	fmt.Println()
+}
+
+// otherSuffixTypes lists the candidate types of a suffix besides the chosen one
+func otherSuffixTypes(s dirinit.ParsedSuffix) []string {
+	var others []string
+	for _, t := range s.Candidates {
+		if t != s.Type {
+			others = append(others, string(t))
+		}
+	}
+	return others
+}
+
+func outputParseJSON(results []parseResult, cfg *dirinit.Config) error {
+	type suffixJSON struct {
+		Text       string   `json:"text"`
+		Type       string   `json:"type"`
+		Candidates []string `json:"candidates"`
+	}
+	type nameJSON struct {
+		Name                string       `json:"name"`
+		Layout              string       `json:"layout,omitempty"`
+		Emoji               string       `json:"emoji,omitempty"`
+		Frontend            string       `json:"frontend,omitempty"`
+		FrontendDescription string       `json:"frontend_description,omitempty"`
+		Backend             string       `json:"backend,omitempty"`
+		BackendDescription  string       `json:"backend_description,omitempty"`
+		Word                string       `json:"word,omitempty"`
+		Category            string       `json:"category,omitempty"`
+		Categories          []string     `json:"categories,omitempty"`
+		Date                string       `json:"date,omitempty"`
+		Suffixes            []suffixJSON `json:"suffixes,omitempty"`
+		Error               string       `json:"error,omitempty"`
+	}
+
+	output := make([]nameJSON, 0, len(results))
+	for _, r := range results {
+		if r.err != nil {
+			output = append(output, nameJSON{Name: r.name, Error: r.err.Error()})
+			continue
+		}
+
+		p := r.parsed
+		entry := nameJSON{
+			Name:                p.Name,
+			Layout:              p.Layout,
+			Emoji:               p.Emoji,
+			Frontend:            p.Frontend,
+			FrontendDescription: codeDescription(cfg, p.Frontend, false),
+			Backend:             p.Backend,
+			BackendDescription:  codeDescription(cfg, p.Backend, true),
+			Word:                p.Word,
+			Category:            p.Category,
+			Categories:          p.Categories,
+			Date:                p.Date,
+		}
+		for _, s := range p.Suffixes {
+			candidates := make([]string, 0, len(s.Candidates))
+			for _, t := range s.Candidates {
+				candidates = append(candidates, string(t))
+			}
+			entry.Suffixes = append(entry.Suffixes, suffixJSON{Text: s.Text, Type: string(s.Type), Candidates: candidates})
+		}
+		output = append(output, entry)
+	}
+
+	jsonData, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to generate JSON: %w", err)
+	}
+
+	fmt.Println(string(jsonData))
+	return nil
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aravindcm49/dir-init/internal/config"
+	"github.com/aravindcm49/dir-init/internal/session"
+	"github.com/aravindcm49/dir-init/internal/utils"
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var replayCreate bool
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().BoolVar(&replayCreate, "create", false, "Create the replayed directories in the current directory")
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <session-file>",
+	Short: "Regenerate the names of a recorded run",
+	Long: `Regenerate exactly the names of an earlier run from its session file.
+
+Every generate and interactive run records its seed and selections in the
+sessions directory ($XDG_STATE_HOME/dir-init/sessions, by default
+~/.local/state/dir-init/sessions). Run with --verbose to see the path of the
+session file.
+
+Examples:
+  dir-init replay ~/.local/state/dir-init/sessions/20250102-150405-interactive-1735830245123456789.yaml
+  dir-init replay session.yaml --create`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Errors from here on are not usage errors
+		cmd.SilenceUsage = true
+
+		sess, err := session.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := dirinit.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		names, replayErr := replaySession(cmd.Context(), sess, cfg)
+		if replayErr != nil && len(names) == 0 {
+			return fmt.Errorf("failed to replay session: %w", replayErr)
+		}
+
+		// Streamed runs do not record their names, so there is nothing to compare
+		if len(sess.Names) > 0 && strings.Join(names, "\n") != strings.Join(sess.Names, "\n") {
+			color.Yellow("⚠️  Replayed names differ from the recorded ones; the config may have changed since the run\n")
+		}
+
+		if !replayCreate {
+			outputText(names)
+		} else {
+			green := color.New(color.FgGreen).Add(color.Bold)
+			failed := 0
+			for _, name := range names {
+				if err := utils.CreateDirectory(name); err != nil {
+					fmt.Printf("❌ Failed to create directory '%s': %v\n", name, err)
+					failed++
+					continue
+				}
+				green.Printf("%s created!\n", name)
+			}
+			if failed > 0 && replayErr == nil {
+				return fmt.Errorf("failed to create %d of %d directories", failed, len(names))
+			}
+		}
+
+		// the names that were found are printed, but fewer than recorded is a failure
+		if replayErr != nil {
+			return fmt.Errorf("failed to replay session: %w", replayErr)
+		}
+		return nil
+	},
+}
+
+// replaySession regenerates the names of a recorded session with its seed, clock and selections
+func replaySession(ctx context.Context, sess *session.Session, cfg *dirinit.Config) ([]string, error) {
+	layout := sess.Layout
+	if layout == "" && sess.Command == "interactive" {
+		layout = dirinit.EnhancedLayout
+	}
+
+	opts := []dirinit.Option{
+		dirinit.WithConfig(cfg),
+		dirinit.WithSeed(sess.Seed),
+		dirinit.WithNow(sess.Time),
+		dirinit.WithLayout(layout),
+		dirinit.WithFrontend(sess.Frontend),
+		dirinit.WithBackend(sess.Backend),
+		dirinit.WithCategory(sess.Category),
+		dirinit.WithWord(sess.Word),
+		dirinit.WithSuffix(sess.SuffixType, sess.SuffixLength),
+		dirinit.WithCount(max(1, sess.Count)),
+		dirinit.WithSampling(sess.Sampling),
+		dirinit.WithComposition(sess.Compose),
+		dirinit.WithCase(sess.Case),
+		dirinit.WithSeparator(sess.Separator),
+		dirinit.WithMaxLength(sess.MaxLength),
+		dirinit.WithMaxWordLength(sess.MaxWord),
+		dirinit.WithMaxCodeLength(sess.MaxCode),
+		dirinit.WithSuffixDigits(sess.SuffixDigits),
+		dirinit.WithEmojis(sess.Emojis),
+		dirinit.WithNumbers(!sess.NoNumbers),
+		dirinit.WithSafeForWork(!sess.NoSFW),
+		dirinit.WithPortability(sess.Portability),
+		dirinit.WithAlliteration(sess.Alliterate),
+		dirinit.WithWordMatch(sess.WordMatch),
+		// The disk may have changed since the run, so only the recorded collisions are avoided
+		dirinit.WithoutDiskCheck(),
+		dirinit.WithReserved(sess.Reserved...),
+		dirinit.WithSequenceStarts(sess.Sequences),
+	}
+	for category, words := range sess.WordLists {
+		opts = append(opts, dirinit.WithWordList(category, words))
+	}
+
+	gen, err := dirinit.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return gen.Generate(ctx)
+}
+
+// recordSession saves a run for later replay and reports its seed and selections to w
+func recordSession(w io.Writer, sess *session.Session) {
+	fmt.Fprintf(w, "[verbose] Seed: %d\n", sess.Seed)
+	fmt.Fprintf(w, "[verbose] Selections: frontend=%s backend=%s category=%s word=%s suffix=%s length=%d count=%d layout=%s\n",
+		sess.Frontend, sess.Backend, sess.Category, sess.Word, sess.SuffixType, sess.SuffixLength, sess.Count, sess.Layout)
+
+	path, err := session.Save(sess, config.GetSessionDir())
+	if err != nil {
+		fmt.Fprintf(w, "[verbose] Could not record session: %v\n", err)
+		return
+	}
+
+	fmt.Fprintf(w, "[verbose] Session recorded: %s (replay with 'dir-init replay %s')\n", path, path)
+}
+
+// reportRejections tells how many words and suffixes the blocklist re-rolled
+func reportRejections(w io.Writer, r dirinit.Rejections) {
+	fmt.Fprintf(w, "[verbose] Blocklist rejected %d words and %d suffixes\n", r.Words, r.Suffixes)
+}
+
+// verboseWriter returns w in verbose mode and a discarding writer otherwise
+func verboseWriter(w io.Writer) io.Writer {
+	if verboseMode {
+		return w
+	}
+	return io.Discard
+}
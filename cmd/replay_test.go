@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aravindcm49/dir-init/internal/session"
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
+)
+
+func TestReplaySession(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		opts []dirinit.Option
+	}{
+		{
+			name: "generate",
+			opts: []dirinit.Option{dirinit.WithCount(5)},
+		},
+		{
+			name: "interactive layout with a sequence",
+			opts: []dirinit.Option{
+				dirinit.WithLayout(dirinit.EnhancedLayout),
+				dirinit.WithFrontend("rct"),
+				dirinit.WithBackend("node"),
+				dirinit.WithCategory("food"),
+				dirinit.WithSuffix("sequence", 0),
+				dirinit.WithCount(3),
+			},
+		},
+		{
+			name: "case, date suffix and blocklist settings",
+			opts: []dirinit.Option{
+				dirinit.WithCase("snake"),
+				dirinit.WithSuffix("date", 0),
+				dirinit.WithSafeForWork(false),
+				dirinit.WithCount(4),
+			},
+		},
+		{
+			name: "word lists and reserved names",
+			opts: []dirinit.Option{
+				dirinit.WithWordList("team", []string{"falcon", "otter", "comet"}),
+				dirinit.WithCategory("team"),
+				dirinit.WithReserved("falcon-a1b2"),
+				dirinit.WithCount(3),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := dirinit.DefaultConfig()
+			opts := append([]dirinit.Option{
+				dirinit.WithConfig(cfg),
+				dirinit.WithSeed(42),
+				dirinit.WithNow(now),
+				dirinit.WithoutDiskCheck(),
+			}, tt.opts...)
+			gen, err := dirinit.New(opts...)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			names, err := gen.Generate(context.Background())
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+
+			path, err := session.Save(newSession("generate", gen, names), t.TempDir())
+			if err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			sess, err := session.Load(path)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			replayed, err := replaySession(context.Background(), sess, cfg)
+			if err != nil {
+				t.Fatalf("replaySession: %v", err)
+			}
+			if !reflect.DeepEqual(replayed, names) {
+				t.Errorf("replayed names = %v, want %v", replayed, names)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
+	"runtime/debug"
 
+	"github.com/aravindcm49/dir-init/internal/config"
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -11,6 +14,20 @@ var (
 	enableInteractive bool
 	avoidInteractive  bool
 	verboseMode       bool
+	seed              int64
+	caseFlag          string
+	separatorFlag     string
+	maxLengthFlag     int
+	maxWordLengthFlag int
+	maxCodeLengthFlag int
+	noSafeForWork     bool
+	suffixDigitsFlag  int
+	emojiFlag         bool
+	noNumbers         bool
+	portabilityFlag   string
+	alliterateFlag    string
+	wordMatchFlag     string
+	configFlag        string
 )
 
 var rootCmd = &cobra.Command{
@@ -20,8 +37,7 @@ var rootCmd = &cobra.Command{
 with customizable categories and alphanumeric suffixes.
 
 Interactive Mode (default):
-- Tech stack selection (fejs, bepy, etc.)
-- Framework selection
+- Frontend and backend selection (rct, node, etc.)
 - Category selection (food, animals, pop, silly, dev)
 - Suffix type selection
 
@@ -33,10 +49,13 @@ It comes with multiple categories of funny names including:
 - Developer-related
 
 Perfect for adding some humor to your development workflow!`,
-	Version: "1.0.0",
+	Version: buildVersion(),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		useConfig()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if !avoidInteractive {
-			interactive(verboseMode)
+			interactive(cmd.Context(), verboseMode, seed)
 		} else {
 			cmd.Help()
 		}
@@ -47,11 +66,86 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&avoidInteractive, "no-interactive", false, "Skip interactive mode")
 	rootCmd.PersistentFlags().BoolVarP(&enableInteractive, "interactive", "i", false, "Start interactive mode (overrides --no-interactive)")
 	rootCmd.PersistentFlags().BoolVarP(&verboseMode, "verbose", "V", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().Int64VarP(&seed, "seed", "S", 0, "Random seed for reproducible results")
+	rootCmd.PersistentFlags().StringVar(&caseFlag, "case", "", "Case style of names (kebab, snake, camel, pascal, dot, upper; overrides config)")
+	rootCmd.PersistentFlags().StringVar(&separatorFlag, "separator", "", "Separator between name parts (overrides config and the case style default)")
+	rootCmd.PersistentFlags().IntVar(&maxLengthFlag, "max-length", 0, "Longest allowed name; words, codes and suffixes are shortened to fit (overrides config)")
+	rootCmd.PersistentFlags().IntVar(&maxWordLengthFlag, "max-word-length", 0, "Skip words longer than this (overrides config)")
+	rootCmd.PersistentFlags().IntVar(&maxCodeLengthFlag, "max-code-length", 0, "Abbreviate frontend and backend codes longer than this (overrides config)")
+	rootCmd.PersistentFlags().IntVar(&suffixDigitsFlag, "suffix-digits", 0, "Digits at the end of pronounceable suffixes, counted in the suffix length (overrides config)")
+	rootCmd.PersistentFlags().BoolVar(&emojiFlag, "emoji", false, "Prefix names with the emoji of their word, e.g. 🍕-pizza-a1b2")
+	rootCmd.PersistentFlags().BoolVar(&noNumbers, "no-numbers", false, "Keep digits out of suffixes")
+	rootCmd.PersistentFlags().StringVar(&portabilityFlag, "portability", "", "Characters names may use (native, ascii, posix; overrides config)")
+	rootCmd.PersistentFlags().StringVar(&alliterateFlag, "alliterate", "", "Pick words starting with the initial of the frontend or backend (any, frontend, backend, both; overrides config)")
+	rootCmd.PersistentFlags().Lookup("alliterate").NoOptDefVal = "any"
+	rootCmd.PersistentFlags().StringVar(&wordMatchFlag, "word-match", "", "Only pick words matching this regular expression, e.g. '^p'")
+	rootCmd.PersistentFlags().StringVar(&configFlag, "config", "", "Config file to use instead of the default location (also $DIR_INIT_CONFIG)")
+	rootCmd.PersistentFlags().BoolVar(&noSafeForWork, "no-sfw", false, "Disable the built-in safe for work filter (the config blocklist still applies)")
+}
+
+// version is set when building a release:
+//
+//	go build -ldflags "-X github.com/aravindcm49/dir-init/cmd.version=v1.2.0"
+var version string
+
+// buildVersion returns the version set at build time, or else the module
+// version recorded by 'go install'
+func buildVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// useConfig selects the config file of --config. Loading the config never
+// writes it: older files are upgraded in memory, and only 'config migrate'
+// and the commands that save the config rewrite them.
+func useConfig() {
+	if configFlag != "" {
+		config.SetDefault(config.NewStore(config.FilePaths(configFlag)))
+	}
+
+	if err := config.Default().Err(); err != nil {
+		color.New(color.FgRed).Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// globalOptions turns the persistent flags into generator options; flags left
+// at their zero value keep the config file's settings
+func globalOptions() []dirinit.Option {
+	opts := []dirinit.Option{
+		dirinit.WithCase(caseFlag),
+		dirinit.WithSeparator(separatorFlag),
+		dirinit.WithMaxLength(maxLengthFlag),
+		dirinit.WithMaxWordLength(maxWordLengthFlag),
+		dirinit.WithMaxCodeLength(maxCodeLengthFlag),
+		dirinit.WithSuffixDigits(suffixDigitsFlag),
+		dirinit.WithPortability(portabilityFlag),
+		dirinit.WithAlliteration(alliterateFlag),
+		dirinit.WithWordMatch(wordMatchFlag),
+	}
+	if emojiFlag {
+		opts = append(opts, dirinit.WithEmojis(true))
+	}
+	if noNumbers {
+		opts = append(opts, dirinit.WithNumbers(false))
+	}
+	if noSafeForWork {
+		opts = append(opts, dirinit.WithSafeForWork(false))
+	}
+	return opts
 }
 
+// Execute runs the command line. Errors returned by commands are printed
+// once, here, and exit with status 1.
 func Execute() {
+	rootCmd.SilenceErrors = true
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		color.New(color.FgRed).Fprintf(os.Stderr, "❌ Error: %v\n", err)
 		os.Exit(1)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsCategory     string
+	statsSuffixType   string
+	statsSuffixLength int
+	statsNames        int
+	statsOutputFormat string
+	statsLayoutFlag   string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how many distinct names a convention can produce",
+	Long: `Show how many distinct names each category can produce with a suffix type
+and length, and the chance that N randomly generated names collide.
+
+Word counts come from the loaded config file.
+
+Examples:
+  dir-init stats
+  dir-init stats -s alpha -l 6 -n 10000
+  dir-init stats -c food -o json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := dirinit.LoadConfig()
+		if err != nil {
+			color.Red("❌ Error loading config: %v\n", err)
+			return
+		}
+
+		gen, err := dirinit.New(append(globalOptions(),
+			dirinit.WithConfig(cfg),
+			dirinit.WithLayout(statsLayoutFlag),
+			dirinit.WithSuffix(statsSuffixType, statsSuffixLength),
+		)...)
+		if err != nil {
+			color.Red("❌ %v\n", err)
+			return
+		}
+
+		categories := []string{statsCategory}
+		if statsCategory == "" {
+			categories = append(dirinit.Categories(cfg), "all")
+		}
+
+		stats := make([]dirinit.Stats, 0, len(categories))
+		for _, category := range categories {
+			stats = append(stats, gen.Stats(category))
+		}
+
+		settings := gen.Settings()
+		if statsOutputFormat == "json" {
+			outputStatsJSON(stats, settings)
+		} else {
+			outputStatsText(stats, settings)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVarP(&statsCategory, "category", "c", "", "Only show this category (default: every category and all)")
+	statsCmd.Flags().StringVarP(&statsSuffixType, "suffix", "s", "mixed", "Suffix type")
+	statsCmd.Flags().IntVarP(&statsSuffixLength, "length", "l", 4, "Suffix length (range depends on the suffix type)")
+	statsCmd.Flags().IntVarP(&statsNames, "names", "n", 1000, "Number of names for the collision probability")
+	statsCmd.Flags().StringVarP(&statsOutputFormat, "output", "o", "text", "Output format (text, json)")
+	statsCmd.Flags().StringVar(&statsLayoutFlag, "layout", "", "Name layout (overrides config)")
+}
+
+func outputStatsText(stats []dirinit.Stats, settings dirinit.Settings) {
+	fmt.Println()
+	color.Cyan("Name space for layout %s with %s suffix (length %d):\n", settings.Layout, settings.SuffixType, settings.SuffixLength)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "CATEGORY\tWORDS\tSUFFIXES\tCOMBINATIONS\tP(COLLISION, %d NAMES)\t50%% AT\t\n", statsNames)
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%.0f\t\n",
+			s.Category, s.Words, s.Suffixes, s.Combinations,
+			formatProbability(s.CollisionProbability(statsNames)),
+			s.NamesForProbability(0.5))
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Println("Collision chances use the birthday bound with the chance of every word under the")
+	fmt.Println("sampling strategy and weights, and ignore names that already exist on disk.")
+}
+
+func outputStatsJSON(stats []dirinit.Stats, settings dirinit.Settings) {
+	type categoryStats struct {
+		Category             string  `json:"category"`
+		Words                uint64  `json:"words"`
+		Suffixes             uint64  `json:"suffixes"`
+		Combinations         uint64  `json:"combinations"`
+		CollisionProbability float64 `json:"collision_probability"`
+		NamesFor50Percent    float64 `json:"names_for_50_percent"`
+	}
+	type Output struct {
+		Layout       string          `json:"layout"`
+		SuffixType   string          `json:"suffix_type"`
+		SuffixLength int             `json:"suffix_length"`
+		Names        int             `json:"names"`
+		Categories   []categoryStats `json:"categories"`
+	}
+
+	output := Output{
+		Layout:       settings.Layout,
+		SuffixType:   settings.SuffixType,
+		SuffixLength: settings.SuffixLength,
+		Names:        statsNames,
+	}
+	for _, s := range stats {
+		output.Categories = append(output.Categories, categoryStats{
+			Category:             s.Category,
+			Words:                s.Words,
+			Suffixes:             s.Suffixes,
+			Combinations:         s.Combinations,
+			CollisionProbability: s.CollisionProbability(statsNames),
+			NamesFor50Percent:    s.NamesForProbability(0.5),
+		})
+	}
+
+	jsonData, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		fmt.Printf("Error generating JSON: %v\n", err)
+		return
+	}
+
+	fmt.Println(string(jsonData))
+}
+
+// formatProbability prints a probability as a percentage that stays readable when it is tiny
+func formatProbability(p float64) string {
+	switch {
+	case p == 0:
+		return "0%"
+	case p < 0.0001:
+		return fmt.Sprintf("%.2e%%", p*100)
+	default:
+		return fmt.Sprintf("%.2f%%", p*100)
+	}
+}
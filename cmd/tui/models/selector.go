@@ -25,6 +25,15 @@ const (
 	ModeCustomInput
 )
 
+// Mark is the state of an item in a multi-selection
+type Mark int
+
+const (
+	MarkNone    Mark = iota
+	MarkInclude      // item is part of the selection
+	MarkExclude      // item is removed from the selection
+)
+
 // SelectorModel is the Bubble Tea model for selection
 type SelectorModel struct {
 	title       string
@@ -36,6 +45,10 @@ type SelectorModel struct {
 	selected    *Item
 	mode        Mode
 
+	// Multi-selection state, Tab cycles an item through the marks
+	multi bool
+	marks map[string]Mark
+
 	// Custom input state
 	customCode string
 	customDesc string
@@ -70,6 +83,15 @@ func NewSelector(title string, items []Item) SelectorModel {
 	}
 }
 
+// NewMultiSelector creates a selector where Tab marks items to include or
+// exclude. Enter without marks selects the item under the cursor.
+func NewMultiSelector(title string, items []Item) SelectorModel {
+	m := NewSelector(title, items)
+	m.multi = true
+	m.marks = make(map[string]Mark)
+	return m
+}
+
 // Init initializes the model
 func (m SelectorModel) Init() tea.Cmd {
 	return textinput.Blink
@@ -97,7 +119,21 @@ func (m SelectorModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.quitting = true
 		return m, tea.Quit
 
+	case tea.KeyTab:
+		if m.multi && len(m.filtered) > 0 {
+			code := m.filtered[m.cursor].Code
+			m.marks[code] = (m.marks[code] + 1) % 3
+			if m.marks[code] == MarkNone {
+				delete(m.marks, code)
+			}
+		}
+
 	case tea.KeyEnter:
+		if len(m.marks) > 0 {
+			// Marked items make the selection
+			m.confirmed = true
+			return m, tea.Quit
+		}
 		if len(m.filtered) > 0 {
 			// Select current item
 			m.selected = &m.filtered[m.cursor]
@@ -265,15 +301,23 @@ func (m SelectorModel) viewSearch() string {
 		}
 	} else {
 		for i, item := range m.filtered {
+			label := item.Code + " - " + item.Description
+			if m.multi {
+				label = [...]string{"[ ] ", "[+] ", "[-] "}[m.marks[item.Code]] + label
+			}
 			if i == m.cursor {
 				selectedItemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true)
-				s.WriteString(selectedItemStyle.Render("> " + item.Code + " - " + item.Description))
+				s.WriteString(selectedItemStyle.Render("> " + label))
 			} else {
 				itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
-				s.WriteString(itemStyle.Render("  " + item.Code + " - " + item.Description))
+				s.WriteString(itemStyle.Render("  " + label))
 			}
 			s.WriteString("\n")
 		}
+		if m.multi {
+			hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+			s.WriteString(hintStyle.Render("\nTab = include [+] / exclude [-] / clear | Enter = confirm\n"))
+		}
 	}
 
 	return s.String()
@@ -303,6 +347,19 @@ func (m SelectorModel) GetSelected() *Item {
 	return m.selected
 }
 
+// Marked returns the codes of the included and excluded items, in list order
+func (m SelectorModel) Marked() (include, exclude []string) {
+	for _, item := range m.allItems {
+		switch m.marks[item.Code] {
+		case MarkInclude:
+			include = append(include, item.Code)
+		case MarkExclude:
+			exclude = append(exclude, item.Code)
+		}
+	}
+	return include, exclude
+}
+
 // ShouldSave returns whether to save to config
 func (m SelectorModel) ShouldSave() bool {
 	return m.saveChoice == 1
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats, detected by the extension of the file
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// Formats lists the config file formats
+var Formats = []string{FormatYAML, FormatJSON, FormatTOML}
+
+// FormatOf returns the format of a config file by its extension: .json files
+// are JSON, .toml files TOML and any other file YAML
+func FormatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	}
+	return FormatYAML
+}
+
+// ParseFormat checks the name of a format, accepting "yml" for YAML
+func ParseFormat(name string) (string, error) {
+	switch name := strings.ToLower(name); name {
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	case FormatJSON, FormatTOML:
+		return name, nil
+	}
+	return "", fmt.Errorf("unknown format '%s' (use yaml, json or toml)", name)
+}
+
+// parseDocument parses a config file into a YAML document node. JSON and TOML
+// files become the tree the same settings written in YAML would, so layers,
+// migrations and validation work the same for every format; only YAML keeps
+// comments, and only YAML and JSON keep line numbers. A document without
+// content stands for an empty file.
+func parseDocument(data []byte, format string) (*yaml.Node, error) {
+	switch format {
+	case FormatJSON:
+		return parseJSON(data)
+	case FormatTOML:
+		return parseTOML(data)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// marshalNode writes a YAML tree in format
+func marshalNode(n *yaml.Node, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, n); err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+			return nil, err
+		}
+		out.WriteString("\n")
+		return out.Bytes(), nil
+
+	case FormatTOML:
+		var v interface{}
+		if err := n.Decode(&v); err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, nil
+		}
+		return toml.Marshal(v)
+	}
+	return yaml.Marshal(n)
+}
+
+// marshalYAML writes a YAML tree indented by indent spaces
+func marshalYAML(n *yaml.Node, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(n); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlIndent returns the indentation of a YAML file: the smallest indentation
+// of its lines, or 2 for a file without nested values
+func yamlIndent(data []byte) int {
+	indent := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if n := len(line) - len(trimmed); n > 0 && (indent == 0 || n < indent) {
+			indent = n
+		}
+	}
+	if indent < 2 || indent > 9 {
+		return 2
+	}
+	return indent
+}
+
+// Marshal returns the config as a config file of format
+func (c *Config) Marshal(format string) ([]byte, error) {
+	var n yaml.Node
+	if err := n.Encode(c); err != nil {
+		return nil, err
+	}
+	return marshalNode(&n, format)
+}
+
+func parseTOML(data []byte) (*yaml.Node, error) {
+	var v map[string]interface{}
+	if err := toml.Unmarshal(data, &v); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, _ := decodeErr.Position()
+			return nil, fmt.Errorf("line %d: %s", row, decodeErr.Error())
+		}
+		return nil, err
+	}
+	if len(v) == 0 {
+		return &yaml.Node{}, nil
+	}
+
+	var root yaml.Node
+	if err := root.Encode(v); err != nil {
+		return nil, err
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&root}}, nil
+}
+
+// jsonParser builds a YAML tree from the tokens of a JSON document, keeping
+// the order of keys and the position of every value
+type jsonParser struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+func parseJSON(data []byte) (*yaml.Node, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &yaml.Node{}, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	p := &jsonParser{data: data, dec: dec}
+
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		line, _ := p.position(int(dec.InputOffset()))
+		return nil, fmt.Errorf("line %d: unexpected data after the top-level value", line)
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}, nil
+}
+
+// value reads the next value, with its object members or array items
+func (p *jsonParser) value() (*yaml.Node, error) {
+	line, column := p.position(int(p.dec.InputOffset()))
+	tok, err := p.dec.Token()
+	if err != nil {
+		return nil, p.syntaxError(err, line)
+	}
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Line: line, Column: column}
+	switch t := tok.(type) {
+	case json.Delim:
+		n.Kind, n.Tag = yaml.SequenceNode, "!!seq"
+		if t == '{' {
+			n.Kind, n.Tag = yaml.MappingNode, "!!map"
+		}
+		for p.dec.More() {
+			if n.Kind == yaml.MappingNode {
+				key, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				n.Content = append(n.Content, key)
+			}
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, item)
+		}
+		if _, err := p.dec.Token(); err != nil { // closing delimiter
+			return nil, p.syntaxError(err, line)
+		}
+	case string:
+		n.Tag, n.Value = "!!str", t
+	case json.Number:
+		n.Tag, n.Value = "!!float", t.String()
+		if _, err := t.Int64(); err == nil {
+			n.Tag = "!!int"
+		}
+	case bool:
+		n.Tag, n.Value = "!!bool", strconv.FormatBool(t)
+	case nil:
+		n.Tag, n.Value = "!!null", "null"
+	}
+	return n, nil
+}
+
+// position returns the line and column of the token following offset, past
+// the whitespace and separators before it
+func (p *jsonParser) position(offset int) (int, int) {
+	for offset < len(p.data) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+	start := bytes.LastIndexByte(p.data[:offset], '\n') + 1
+	return 1 + bytes.Count(p.data[:offset], []byte("\n")), 1 + utf8.RuneCount(p.data[start:offset])
+}
+
+// syntaxError adds the line to an error of the JSON decoder, in the form the
+// YAML package uses
+func (p *jsonParser) syntaxError(err error, line int) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, _ = p.position(int(syntaxErr.Offset) - 1)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("line %d: %v", line, err)
+}
+
+// writeJSON writes a YAML tree as compact JSON, keeping the order of keys
+func writeJSON(buf *bytes.Buffer, n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		return writeJSON(buf, n.Content[0])
+
+	case yaml.AliasNode:
+		return writeJSON(buf, n.Alias)
+
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(n.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+
+	default:
+		var v interface{}
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// layerFiles are the files of a test store; empty files are not written
+type layerFiles struct {
+	system  string
+	user    string
+	words   map[string]string // word list file name -> content
+	project string
+}
+
+// testStore writes files to a temporary directory and returns a store using
+// them, with the working directory in the project directory
+func testStore(t *testing.T, files layerFiles) *Store {
+	t.Helper()
+	dir := t.TempDir()
+
+	system := filepath.Join(dir, "etc", "config.yaml")
+	if files.system != "" {
+		writeTestFile(t, system, files.system)
+	}
+	saved := systemConfigPath
+	systemConfigPath = system
+	t.Cleanup(func() { systemConfigPath = saved })
+
+	user := filepath.Join(dir, "user", "config.yaml")
+	if files.user != "" {
+		writeTestFile(t, user, files.user)
+	}
+	for name, content := range files.words {
+		writeTestFile(t, filepath.Join(dir, "user", "words", name), content)
+	}
+
+	project := filepath.Join(dir, "project")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if files.project != "" {
+		writeTestFile(t, filepath.Join(project, ".dir-init.yaml"), files.project)
+	}
+	t.Chdir(project)
+
+	return NewStore(FilePaths(user))
+}
+
+// writeTestFile writes content to path, creating its directory
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// listFiles returns the files below dir with their contents
+func listFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		files[path] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
@@ -0,0 +1,611 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Layer names, from the lowest to the highest precedence
+const (
+	LayerSystem  = "system"  // /etc/dir-init/config.yaml
+	LayerUser    = "user"    // the config file and word lists of the Store
+	LayerProject = "project" // .dir-init.yaml (or .json, .toml) in the working directory or a parent
+	LayerEnv     = "env"     // DIR_INIT_* environment variables
+	LayerFlags   = "flags"   // command line flags
+)
+
+// ProjectConfigNames are the project-local config files, found by walking up
+// from the working directory. A directory with several uses the first.
+var ProjectConfigNames = []string{".dir-init.yaml", ".dir-init.json", ".dir-init.toml"}
+
+// EnvPrefix starts the environment variables that override top-level settings,
+// such as DIR_INIT_CASE=snake or DIR_INIT_MAX_LENGTH=20
+const EnvPrefix = "DIR_INIT_"
+
+// systemConfigPath holds defaults for every user of the machine
+var systemConfigPath = "/etc/dir-init/config.yaml"
+
+// Merge directives are YAML tags on a value of a higher layer. Without one,
+// mappings merge key by key, lists are appended to (entries with the same
+// value, or the same code for frontends and backends, are overridden) and
+// other values replace those of lower layers.
+const (
+	tagReplace = "!replace" // replace the value of lower layers instead of merging
+	tagRemove  = "!remove"  // remove the listed entries or keys; on a single value, unset it
+)
+
+// Layer is one source of config values
+type Layer struct {
+	Name   string // one of the Layer* names
+	Source string // file, environment variable or flag the values come from
+	label  string // origin shown by Annotated
+	node   *yaml.Node
+}
+
+// FlagLayer is a layer setting the config key to the value of a command line
+// flag. Flags without a key, such as --word-match, give a layer without
+// values, so that they are still listed among the layers.
+func FlagLayer(flag, key, value string) Layer {
+	if key == "" {
+		return Layer{
+			Name:   LayerFlags,
+			Source: fmt.Sprintf("%s=%s (not a config setting)", flag, value),
+			label:  LayerFlags + " " + flag,
+			node:   &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"},
+		}
+	}
+	return Layer{Name: LayerFlags, Source: flag, label: LayerFlags + " " + flag, node: settingNode(key, value)}
+}
+
+// IsSettingKey reports whether key is a single-valued top-level setting, which
+// environment variables and flags can set
+func IsSettingKey(key string) bool {
+	return slices.Contains(settingKeys(), key)
+}
+
+// LayeredConfig is a config merged from its layers
+type LayeredConfig struct {
+	*Config
+	Layers []Layer // layers that were found, lowest precedence first
+
+	root    *yaml.Node            // merged values
+	origins map[*yaml.Node]*Layer // merged value -> layer it came from
+}
+
+// LoadLayered merges the system, user and project configs, the user's word
+// list files and the DIR_INIT_* environment variables, followed by extra
+// layers such as FlagLayer. Missing layers are skipped; a missing user config
+// is read as DefaultConfig. Older files are upgraded in memory, never written.
+func LoadLayered(extra ...Layer) (*LayeredConfig, error) {
+	return Default().LoadLayered(extra...)
+}
+
+// LoadLayered merges the layers of the store, with the store's user config
+// and word lists as the user layer
+func (s *Store) LoadLayered(extra ...Layer) (*LayeredConfig, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.mu.RLock()
+	layers, err := s.findLayers()
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	layers = append(layers, extra...)
+
+	m := &merger{origins: make(map[*yaml.Node]*Layer)}
+	var root *yaml.Node
+	for i := range layers {
+		l := &layers[i]
+		normalizeLayer(l.node)
+		if err := validateLayer(l.node); err != nil {
+			return nil, fmt.Errorf("%s: %w", l.Source, err)
+		}
+		root = m.merge(root, l.node, l)
+	}
+
+	var config Config
+	if root != nil {
+		if err := root.Decode(&config); err != nil {
+			return nil, fmt.Errorf("failed to merge config layers: %w", err)
+		}
+	}
+	if config.Frameworks == nil {
+		config.Frameworks = make(map[string][]Framework)
+	}
+	if config.Categories == nil {
+		config.Categories = make(map[string]Category)
+	}
+
+	return &LayeredConfig{Config: &config, Layers: layers, root: root, origins: m.origins}, nil
+}
+
+// Annotated returns the merged config as YAML, every value commented with the
+// layer it came from
+func (l *LayeredConfig) Annotated() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# Layers, lowest precedence first:\n")
+	for _, layer := range l.Layers {
+		fmt.Fprintf(&buf, "#   %-8s %s\n", layer.Name, layer.Source)
+	}
+	if l.root == nil {
+		return buf.Bytes(), nil
+	}
+	buf.WriteString("\n")
+
+	l.annotate(l.root)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(l.root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// annotate sets the origin of every value as its line comment
+func (l *LayeredConfig) annotate(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			l.annotate(n.Content[i])
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			l.annotate(item)
+		}
+	case yaml.ScalarNode:
+		if origin := l.origins[n]; origin != nil {
+			n.LineComment = origin.label
+		}
+	}
+}
+
+// findLayers reads every layer that exists, lowest precedence first
+func (s *Store) findLayers() ([]Layer, error) {
+	var layers []Layer
+
+	if _, err := os.Stat(systemConfigPath); err == nil {
+		l, err := readFileLayer(LayerSystem, systemConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, l)
+	}
+
+	paths := s.userPaths()
+	user, err := userLayer(paths.Config)
+	if err != nil {
+		return nil, err
+	}
+	layers = append(layers, user)
+
+	words, err := wordFileLayers(paths.Words)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load word lists: %w", err)
+	}
+	layers = append(layers, words...)
+
+	if path := FindProjectConfig(); path != "" {
+		l, err := readFileLayer(LayerProject, path)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, l)
+	}
+
+	env, err := envLayers()
+	if err != nil {
+		return nil, err
+	}
+	return append(layers, env...), nil
+}
+
+// FindProjectConfig returns the nearest project config file in the working
+// directory or one of its parents, or "" when there is none
+func FindProjectConfig() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		for _, name := range ProjectConfigNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func readFileLayer(name, path string) (Layer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Layer{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	doc, err := parseDocument(data, FormatOf(path))
+	if err != nil {
+		return Layer{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if len(doc.Content) > 0 && doc.Content[0].Kind != yaml.ScalarNode {
+		node = doc.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return Layer{}, fmt.Errorf("failed to parse config file %s: expected a mapping of settings", path)
+	}
+
+	// files are upgraded in memory; only 'config migrate' rewrites them
+	if _, err := migrateNode(node, nil); err != nil {
+		return Layer{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return Layer{Name: name, Source: path, label: name, node: node}, nil
+}
+
+// wordFileLayers turns every word list file into a layer adding its words to
+// the category named after the file
+func wordFileLayers(dir string) ([]Layer, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var layers []Layer
+	var errs []error
+	for _, path := range paths {
+		words, err := ReadWordFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, w := range words {
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: w})
+		}
+		category := strings.TrimSuffix(filepath.Base(path), ".txt")
+		node := mappingNode("categories", mappingNode(category, mappingNode("words", list)))
+		layers = append(layers, Layer{
+			Name:   LayerUser,
+			Source: path,
+			label:  LayerUser + " words/" + filepath.Base(path),
+			node:   node,
+		})
+	}
+	return layers, errors.Join(errs...)
+}
+
+// envLayers returns a layer for every DIR_INIT_* variable naming a top-level
+// setting. Values that do not fit the type of their setting are reported by
+// variable, such as "DIR_INIT_MAX_LENGTH: must be an integer".
+func envLayers() ([]Layer, error) {
+	var layers []Layer
+	var errs []error
+	for _, key := range settingKeys() {
+		name := EnvPrefix + strings.ToUpper(key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		value, err := settingValue(key, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		layers = append(layers, Layer{Name: LayerEnv, Source: name, label: LayerEnv + " " + name, node: settingNode(key, value)})
+	}
+	return layers, errors.Join(errs...)
+}
+
+// settingValue checks a value for the setting key against the type of the
+// setting, returning it in the form YAML reads as that type
+func settingValue(key, value string) (string, error) {
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if k, ok := settingKey(field); !ok || k != key {
+			continue
+		}
+		kind := field.Type.Kind()
+		if kind == reflect.Pointer {
+			kind = field.Type.Elem().Kind()
+		}
+		switch kind {
+		case reflect.Int:
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return "", fmt.Errorf("must be an integer")
+			}
+			return strconv.Itoa(n), nil
+		case reflect.Bool:
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return "", fmt.Errorf("must be true or false")
+			}
+			return strconv.FormatBool(b), nil
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return "", fmt.Errorf("must be a number")
+			}
+			return strconv.FormatFloat(f, 'g', -1, 64), nil
+		}
+	}
+	return value, nil
+}
+
+// settingKeys returns the YAML keys of the single-valued top-level settings,
+// which are the ones environment variables and flags can set
+func settingKeys() []string {
+	var keys []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if key, ok := settingKey(t.Field(i)); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// settingKey returns the YAML key of a single-valued config field
+func settingKey(field reflect.StructField) (string, bool) {
+	key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Bool, reflect.Float64:
+		return key, true
+	}
+	return "", false
+}
+
+// settingNode builds {key: value}. Values of string settings stay strings,
+// other values are resolved like plain YAML scalars.
+func settingNode(key, value string) *yaml.Node {
+	tag := ""
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if k, ok := settingKey(t.Field(i)); ok && k == key && t.Field(i).Type.Kind() == reflect.String {
+			tag = "!!str"
+		}
+	}
+	return mappingNode(key, &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value})
+}
+
+func mappingNode(key string, value *yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	}}
+}
+
+// normalizeLayer writes categories in their mapping form, so that a plain
+// word list merges with a category of a lower layer written as a mapping
+func normalizeLayer(n *yaml.Node) {
+	i := mappingIndex(n, "categories")
+	if i < 0 || n.Content[i+1].Kind != yaml.MappingNode {
+		return
+	}
+
+	categories := n.Content[i+1]
+	for j := 1; j < len(categories.Content); j += 2 {
+		cat := categories.Content[j]
+		if cat.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		// "food: !replace [...]" replaces the category, "food: !remove [...]" removes words
+		wrapped := mappingNode("words", cat)
+		if cat.Tag == tagReplace {
+			wrapped.Tag = tagReplace
+			cat.Tag = "!!seq"
+		}
+		categories.Content[j] = wrapped
+	}
+}
+
+// validateLayer decodes a layer on its own, so type errors name the layer
+func validateLayer(n *yaml.Node) error {
+	var check Config
+	return withoutDirectives(n).Decode(&check)
+}
+
+// withoutDirectives copies n without the values tagged !remove and without merge tags
+func withoutDirectives(n *yaml.Node) *yaml.Node {
+	c := *n
+	c.Tag = plainTag(n)
+	c.Content = nil
+	for i := 0; i < len(n.Content); i++ {
+		if n.Kind == yaml.MappingNode && i+1 < len(n.Content) {
+			key, value := n.Content[i], n.Content[i+1]
+			i++
+			if value.Tag == tagRemove {
+				continue
+			}
+			c.Content = append(c.Content, key, withoutDirectives(value))
+			continue
+		}
+		c.Content = append(c.Content, withoutDirectives(n.Content[i]))
+	}
+	return &c
+}
+
+// plainTag replaces merge directives with the default tag of the node kind
+func plainTag(n *yaml.Node) string {
+	if n.Tag != tagReplace && n.Tag != tagRemove {
+		return n.Tag
+	}
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "!!map"
+	case yaml.SequenceNode:
+		return "!!seq"
+	}
+	return ""
+}
+
+// merger merges layers into one tree, remembering where every value came from
+type merger struct {
+	origins map[*yaml.Node]*Layer
+}
+
+// merge merges src over dst and returns the result, or nil when src removes
+// the value. dst is updated in place.
+func (m *merger) merge(dst, src *yaml.Node, layer *Layer) *yaml.Node {
+	switch src.Tag {
+	case tagReplace:
+		return m.clone(src, layer)
+	case tagRemove:
+		return remove(dst, src)
+	}
+	if dst == nil || dst.Kind != src.Kind || src.Kind == yaml.ScalarNode {
+		return m.clone(src, layer)
+	}
+
+	switch src.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+			j := mappingIndex(dst, key.Value)
+			if j < 0 {
+				if value.Tag != tagRemove {
+					dst.Content = append(dst.Content, m.clone(key, layer), m.clone(value, layer))
+				}
+				continue
+			}
+			if merged := m.merge(dst.Content[j+1], value, layer); merged != nil {
+				dst.Content[j+1] = merged
+			} else {
+				dst.Content = append(dst.Content[:j], dst.Content[j+2:]...)
+			}
+		}
+
+	case yaml.SequenceNode:
+		for _, item := range src.Content {
+			if j := sequenceIndex(dst, item); j >= 0 {
+				dst.Content[j] = m.merge(dst.Content[j], item, layer)
+				continue
+			}
+			dst.Content = append(dst.Content, m.clone(item, layer))
+		}
+	}
+	return dst
+}
+
+// remove applies a !remove directive: listed entries are removed from lists,
+// listed keys from mappings, and a single value is unset
+func remove(dst, src *yaml.Node) *yaml.Node {
+	if dst == nil || src.Kind == yaml.ScalarNode {
+		return nil
+	}
+
+	removed := make(map[string]struct{})
+	for i, item := range src.Content {
+		if src.Kind == yaml.MappingNode && i%2 == 1 {
+			continue
+		}
+		removed[itemKey(item)] = struct{}{}
+	}
+
+	kept := dst.Content[:0]
+	switch dst.Kind {
+	case yaml.SequenceNode:
+		for _, item := range dst.Content {
+			if _, ok := removed[itemKey(item)]; !ok {
+				kept = append(kept, item)
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(dst.Content); i += 2 {
+			if _, ok := removed[dst.Content[i].Value]; !ok {
+				kept = append(kept, dst.Content[i], dst.Content[i+1])
+			}
+		}
+	default:
+		return dst
+	}
+	dst.Content = kept
+	return dst
+}
+
+// clone deep-copies n without comments, merge tags or flow style, recording layer as its origin
+func (m *merger) clone(n *yaml.Node, layer *Layer) *yaml.Node {
+	c := &yaml.Node{
+		Kind:   n.Kind,
+		Tag:    plainTag(n),
+		Value:  n.Value,
+		Style:  n.Style &^ (yaml.FlowStyle | yaml.TaggedStyle),
+		Line:   n.Line,
+		Column: n.Column,
+	}
+	for _, child := range n.Content {
+		c.Content = append(c.Content, m.clone(child, layer))
+	}
+	if c.Kind == yaml.ScalarNode {
+		c.Style = 0 // quote only where needed, so emoji stay readable
+		m.origins[c] = layer
+	}
+	return c
+}
+
+// mappingIndex returns the index of key in a mapping node, or -1
+func mappingIndex(n *yaml.Node, key string) int {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// sequenceIndex returns the index of the entry of a list that item overrides, or -1
+func sequenceIndex(list, item *yaml.Node) int {
+	key := itemKey(item)
+	if key == "" {
+		return -1
+	}
+	for i, existing := range list.Content {
+		if existing.Kind == item.Kind && itemKey(existing) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// itemKey identifies a list entry: its value, or the code of a frontend,
+// backend, tech stack or framework
+func itemKey(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Value
+	case yaml.MappingNode:
+		if i := mappingIndex(n, "code"); i >= 0 {
+			return n.Content[i+1].Value
+		}
+	}
+	return ""
+}
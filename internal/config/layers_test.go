@@ -0,0 +1,198 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadLayered(t *testing.T) {
+	tests := []struct {
+		name  string
+		files layerFiles
+		env   map[string]string
+		flags []Layer
+
+		wantCase      string
+		wantFrontends []string
+		wantBackends  []string
+		wantWords     map[string][]string
+		wantLines     []string // lines of Annotated, without indentation
+	}{
+		{
+			name: "higher layers override settings",
+			files: layerFiles{
+				system:  "case: snake\nseparator: _\n",
+				user:    "case: kebab\n",
+				project: "case: camel\n",
+			},
+			env:      map[string]string{"DIR_INIT_CASE": "upper"},
+			wantCase: "upper",
+			wantLines: []string{
+				"case: upper # env DIR_INIT_CASE",
+				"separator: _ # system",
+			},
+		},
+		{
+			name:     "flags win over the environment",
+			files:    layerFiles{user: "case: kebab\n"},
+			env:      map[string]string{"DIR_INIT_CASE": "snake"},
+			flags:    []Layer{FlagLayer("--case", "case", "dot")},
+			wantCase: "dot",
+			wantLines: []string{
+				"#   env      DIR_INIT_CASE",
+				"#   flags    --case",
+				"case: dot # flags --case",
+			},
+		},
+		{
+			name: "list entries are appended or overridden by code",
+			files: layerFiles{
+				system: "frontends:\n  - {code: rct, description: React}\n",
+				user:   "frontends:\n  - {code: rct, description: React 19}\n  - {code: vue, description: Vue}\n",
+			},
+			wantFrontends: []string{"rct", "vue"},
+			wantLines:     []string{"description: React 19 # user", "description: Vue # user"},
+		},
+		{
+			name: "word list files extend categories",
+			files: layerFiles{
+				user:  "categories:\n  food: [pizza]\n",
+				words: map[string]string{"food.txt": "taco\n", "space.txt": "comet\n"},
+			},
+			wantWords: map[string][]string{"food": {"pizza", "taco"}, "space": {"comet"}},
+			wantLines: []string{"- pizza # user", "- taco # user words/food.txt"},
+		},
+		{
+			name: "replace and remove directives",
+			files: layerFiles{
+				system:  "categories:\n  food: [pizza, taco, sushi]\nfrontends:\n  - {code: rct}\n",
+				user:    "categories:\n  food: !remove [taco]\n",
+				project: "frontends: !replace\n  - {code: svl}\n",
+			},
+			wantFrontends: []string{"svl"},
+			wantWords:     map[string][]string{"food": {"pizza", "sushi"}},
+			wantLines:     []string{"- code: svl # project"},
+		},
+		{
+			name: "directives in a file without a version",
+			files: layerFiles{
+				user:    "version: 1\nfrontends:\n  - {code: rct}\n  - {code: ng}\n  - {code: vue}\nbackends:\n  - {code: node}\n  - {code: go}\n",
+				project: "case: snake\nfrontends: !remove [ng, vue]\nbackends: !remove [node]\n",
+			},
+			wantCase:      "snake",
+			wantFrontends: []string{"rct"},
+			wantBackends:  []string{"go"},
+		},
+		{
+			name:      "missing user config reads as the defaults",
+			wantLines: []string{"#   user     built-in defaults", "- cherry-pick # defaults"},
+		},
+		{
+			name:      "flags that are not settings are listed",
+			files:     layerFiles{user: "case: kebab\n"},
+			flags:     []Layer{FlagLayer("--word-match", "", "^p")},
+			wantCase:  "kebab",
+			wantLines: []string{"#   flags    --word-match=^p (not a config setting)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testStore(t, tt.files)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			l, err := s.LoadLayered(tt.flags...)
+			if err != nil {
+				t.Fatalf("LoadLayered: %v", err)
+			}
+
+			if tt.wantCase != "" && l.Case != tt.wantCase {
+				t.Errorf("case = %q, want %q", l.Case, tt.wantCase)
+			}
+			if tt.wantFrontends != nil {
+				var codes []string
+				for _, fe := range l.Frontends {
+					codes = append(codes, fe.Code)
+				}
+				if !reflect.DeepEqual(codes, tt.wantFrontends) {
+					t.Errorf("frontends = %v, want %v", codes, tt.wantFrontends)
+				}
+			}
+			if tt.wantBackends != nil {
+				var codes []string
+				for _, be := range l.Backends {
+					codes = append(codes, be.Code)
+				}
+				if !reflect.DeepEqual(codes, tt.wantBackends) {
+					t.Errorf("backends = %v, want %v", codes, tt.wantBackends)
+				}
+			}
+			for category, want := range tt.wantWords {
+				if got := l.Categories[category].Words; !reflect.DeepEqual(got, want) {
+					t.Errorf("words of %s = %v, want %v", category, got, want)
+				}
+			}
+
+			annotated, err := l.Annotated()
+			if err != nil {
+				t.Fatalf("Annotated: %v", err)
+			}
+			lines := make(map[string]bool)
+			for _, line := range strings.Split(string(annotated), "\n") {
+				lines[strings.TrimSpace(line)] = true
+			}
+			for _, want := range tt.wantLines {
+				if !lines[want] {
+					t.Errorf("Annotated() has no line %q:\n%s", want, annotated)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvLayers(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{"DIR_INIT_CASE", "snake", "case: snake", ""},
+		{"DIR_INIT_SEPARATOR", "123", "separator: \"123\"", ""},
+		{"DIR_INIT_MAX_LENGTH", " 20 ", "max_length: 20", ""},
+		{"DIR_INIT_MAX_LENGTH", "twenty", "", "DIR_INIT_MAX_LENGTH: must be an integer"},
+		{"DIR_INIT_EMOJIS", "1", "emojis: true", ""},
+		{"DIR_INIT_EMOJIS", "sometimes", "", "DIR_INIT_EMOJIS: must be true or false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+
+			layers, err := envLayers()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("envLayers() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("envLayers: %v", err)
+			}
+			if len(layers) != 1 || layers[0].Source != tt.name {
+				t.Fatalf("envLayers() = %+v, want one layer from %s", layers, tt.name)
+			}
+
+			data, err := marshalNode(layers[0].node, FormatYAML)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(data)); got != tt.want {
+				t.Errorf("layer = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
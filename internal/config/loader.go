@@ -9,48 +9,115 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// Store reads and writes the config files at one set of Paths. The
+// package-level functions use the Default store.
+type Store struct {
+	mu    sync.RWMutex
+	paths Paths
+	err   error // why the default paths could not be determined
+}
+
+// NewStore returns a store for paths
+func NewStore(paths Paths) *Store {
+	return &Store{paths: paths}
+}
+
 var (
-	configMutex sync.RWMutex
-	configPath  string
+	defaultMu    sync.Mutex
+	defaultStore *Store
 )
 
-func init() {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		configPath = ".dir-init/config.yaml"
-	} else {
-		configPath = filepath.Join(home, ".dir-init", "config.yaml")
+// Default returns the store used by the package-level functions: the config
+// file named by DIR_INIT_CONFIG, or else the XDG locations of DefaultPaths
+func Default() *Store {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultStore == nil {
+		if path := os.Getenv(ConfigEnv); path != "" {
+			defaultStore = NewStore(FilePaths(path))
+		} else {
+			paths, err := DefaultPaths()
+			defaultStore = &Store{paths: paths, err: err}
+		}
 	}
+	return defaultStore
+}
+
+// SetDefault replaces the store used by the package-level functions, as the
+// --config flag does
+func SetDefault(store *Store) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultStore = store
+}
+
+// Paths returns the files and directories of the store
+func (s *Store) Paths() Paths {
+	return s.paths
+}
+
+// Err reports why the store has no usable paths, such as a missing home directory
+func (s *Store) Err() error {
+	return s.err
 }
 
 // GetConfigPath returns the path to the config file
 func GetConfigPath() string {
-	return configPath
+	return Default().paths.Config
+}
+
+// GetSessionDir returns the directory where run sessions are recorded for replay
+func GetSessionDir() string {
+	return Default().paths.Sessions
+}
+
+// GetWordsDir returns the directory of plain-text word lists, one file per category
+func GetWordsDir() string {
+	return Default().paths.Words
 }
 
-// LoadConfig loads the user configuration from ~/.dir-init/config.yaml
+// LoadConfig loads the configuration merged from all of its layers: the
+// system config, the user config and its word lists, the nearest project
+// .dir-init.yaml and the DIR_INIT_* environment variables (see LoadLayered)
 func LoadConfig() (*Config, error) {
-	configMutex.RLock()
-	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		configMutex.RUnlock()
-		// Initialize with defaults if it doesn't exist
-		if err := InitConfig(); err != nil {
-			return nil, fmt.Errorf("failed to initialize config: %w", err)
-		}
-		configMutex.RLock()
+	return Default().LoadConfig()
+}
+
+// LoadConfig loads the configuration of the store merged from all of its layers
+func (s *Store) LoadConfig() (*Config, error) {
+	layered, err := s.LoadLayered()
+	if err != nil {
+		return nil, err
 	}
-	defer configMutex.RUnlock()
+	return layered.Config, nil
+}
 
-	// Read config file
-	data, err := os.ReadFile(configPath)
+// LoadUserConfig loads the user config file and merges the word lists of the
+// words directory into its categories. Commands that change the config load
+// it with LoadUserConfig and write it back with SaveConfig, so values of other
+// layers are never saved into the user file.
+func LoadUserConfig() (*Config, error) {
+	return Default().LoadUserConfig()
+}
+
+// LoadUserConfig loads the user config file of the store with its word lists
+func (s *Store) LoadUserConfig() (*Config, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	paths := s.userPaths()
+	layer, err := userLayer(paths.Config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
-	// Parse YAML
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	if err := layer.node.Decode(&config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
@@ -59,41 +126,125 @@ func LoadConfig() (*Config, error) {
 		config.Frameworks = make(map[string][]Framework)
 	}
 	if config.Categories == nil {
-		config.Categories = make(map[string][]string)
+		config.Categories = make(map[string]Category)
+	}
+
+	if err := config.loadWordFiles(paths.Words); err != nil {
+		return nil, fmt.Errorf("failed to load word lists: %w", err)
 	}
 
 	return &config, nil
 }
 
-// SaveConfig saves the configuration to ~/.dir-init/config.yaml
+// userPaths returns the config file and word lists of the user layer: those
+// of the store, or those of an older ~/.dir-init directory that Migrate has
+// not moved yet. Loading never writes, so they are read where they are.
+func (s *Store) userPaths() Paths {
+	paths := s.paths
+	if paths.Legacy == "" {
+		return paths
+	}
+	if _, err := os.Stat(paths.Config); os.IsNotExist(err) {
+		if legacy := filepath.Join(paths.Legacy, "config.yaml"); fileExists(legacy) {
+			paths.Config = legacy
+		}
+	}
+	if _, err := os.Stat(paths.Words); os.IsNotExist(err) {
+		if legacy := filepath.Join(paths.Legacy, "words"); fileExists(legacy) {
+			paths.Words = legacy
+		}
+	}
+	return paths
+}
+
+// UserConfigPath returns the user config file that is loaded: the config
+// file of the store, or the one of an older ~/.dir-init directory
+func (s *Store) UserConfigPath() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.userPaths().Config
+}
+
+// userLayer reads the user config file, upgraded to CurrentVersion in memory,
+// or the defaults when the file does not exist
+func userLayer(path string) (Layer, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		var node yaml.Node
+		if err := node.Encode(DefaultConfig()); err != nil {
+			return Layer{}, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return Layer{Name: LayerUser, Source: "built-in defaults", label: "defaults", node: &node}, nil
+	}
+	return readFileLayer(LayerUser, path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// SaveConfig saves the configuration to the user config file, in the format
+// of its extension
 func SaveConfig(config *Config) error {
-	configMutex.Lock()
-	defer configMutex.Unlock()
+	return Default().SaveConfig(config)
+}
+
+// SaveConfig saves the configuration to the user config file of the store.
+// The files of an older ~/.dir-init directory are moved first, and a file of
+// an older version is copied to config.yaml.v<version>-<time>.bak before it
+// is overwritten.
+func (s *Store) SaveConfig(config *Config) error {
+	if s.err != nil {
+		return s.err
+	}
+	if _, err := s.Migrate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	// Create directory if it doesn't exist
-	dir := filepath.Dir(configPath)
+	dir := filepath.Dir(s.paths.Config)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Marshal to YAML
-	data, err := yaml.Marshal(config)
+	// Marshal, leaving the words of word list files in their files
+	format := FormatOf(s.paths.Config)
+	file := config.withoutWordFiles()
+	file.Version = CurrentVersion
+	if len(file.TechStacks) > 0 || len(file.Frameworks) > 0 {
+		// tech stacks are moved to frontends and backends by the version 1 upgrade
+		file.Version = 0
+	}
+	data, err := file.Marshal(format)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	// Add header comment
-	header := "# dir-init Custom Collections\n# Auto-generated and manually editable\n\n"
-	finalData := append([]byte(header), data...)
+	// Add header comment, which JSON has no syntax for
+	if format != FormatJSON {
+		header := "# dir-init Custom Collections\n# Auto-generated and manually editable\n\n"
+		data = append([]byte(header), data...)
+	}
 
+	if _, err := backupOlder(s.paths.Config); err != nil {
+		return err
+	}
+	return writeConfigFile(s.paths.Config, data)
+}
+
+// writeConfigFile replaces a config file atomically
+func writeConfigFile(path string, data []byte) error {
 	// Write to temp file first (atomic write)
-	tempPath := configPath + ".tmp"
-	if err := os.WriteFile(tempPath, finalData, 0644); err != nil {
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	// Rename temp file to actual config file
-	if err := os.Rename(tempPath, configPath); err != nil {
+	if err := os.Rename(tempPath, path); err != nil {
 		os.Remove(tempPath) // Clean up temp file
 		return fmt.Errorf("failed to save config file: %w", err)
 	}
@@ -103,7 +254,12 @@ func SaveConfig(config *Config) error {
 
 // SaveTechStack adds a tech stack to the config
 func SaveTechStack(code, description string) error {
-	config, err := LoadConfig()
+	return Default().SaveTechStack(code, description)
+}
+
+// SaveTechStack adds a tech stack to the user config file of the store
+func (s *Store) SaveTechStack(code, description string) error {
+	config, err := s.LoadUserConfig()
 	if err != nil {
 		return err
 	}
@@ -121,12 +277,17 @@ func SaveTechStack(code, description string) error {
 		Description: description,
 	})
 
-	return SaveConfig(config)
+	return s.SaveConfig(config)
 }
 
 // SaveFramework adds a framework to the config
 func SaveFramework(techStack, code, description string) error {
-	config, err := LoadConfig()
+	return Default().SaveFramework(techStack, code, description)
+}
+
+// SaveFramework adds a framework to the user config file of the store
+func (s *Store) SaveFramework(techStack, code, description string) error {
+	config, err := s.LoadUserConfig()
 	if err != nil {
 		return err
 	}
@@ -149,43 +310,68 @@ func SaveFramework(techStack, code, description string) error {
 		Description: description,
 	})
 
-	return SaveConfig(config)
+	return s.SaveConfig(config)
 }
 
 // SaveCategoryWord adds a word to a category in the config
 func SaveCategoryWord(category, word string) error {
-	config, err := LoadConfig()
+	return Default().SaveCategoryWord(category, word)
+}
+
+// SaveCategoryWord adds a word to a category to the user config file of the store
+func (s *Store) SaveCategoryWord(category, word string) error {
+	if err := ValidateWord(word); err != nil {
+		return err
+	}
+	config, err := s.LoadUserConfig()
 	if err != nil {
 		return err
 	}
 
-	// Initialize category if needed
-	if config.Categories[category] == nil {
-		config.Categories[category] = []string{}
-	}
+	cat := config.Categories[category]
 
 	// Check if already exists
-	for _, w := range config.Categories[category] {
+	for _, w := range cat.Words {
 		if w == word {
 			return fmt.Errorf("word '%s' already exists in category '%s'", word, category)
 		}
 	}
 
 	// Add new word
-	config.Categories[category] = append(config.Categories[category], word)
+	cat.Words = append(cat.Words, word)
+	config.Categories[category] = cat
 
-	return SaveConfig(config)
+	return s.SaveConfig(config)
 }
 
 // InitConfig creates an example config file
 func InitConfig() error {
+	return Default().InitConfig()
+}
+
+// InitConfig creates the user config file of the store with the defaults,
+// unless there is one or Migrate moves one from ~/.dir-init
+func (s *Store) InitConfig() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if _, err := s.Migrate(); err != nil {
+		return err
+	}
+
 	// Check if config already exists
-	if _, err := os.Stat(configPath); err == nil {
+	if _, err := os.Stat(s.paths.Config); err == nil {
 		return nil // Already exists, nothing to do
 	}
 
-	// Create default config with all values
-	defaultConfig := &Config{
+	return s.SaveConfig(DefaultConfig())
+}
+
+// DefaultConfig returns the config used when there is no user config file,
+// which InitConfig writes
+func DefaultConfig() *Config {
+	return &Config{
 		Frontends: []Frontend{
 			{Code: "rct", Description: "React"},
 			{Code: "vue", Description: "Vue.js"},
@@ -223,8 +409,8 @@ func InitConfig() error {
 			{Code: "laravel", Description: "Laravel"},
 			{Code: "none", Description: "No Backend"},
 		},
-		Categories: map[string][]string{
-			"food": {
+		Categories: map[string]Category{
+			"food": {Description: "Food, cooking and beverage words", Order: 1, Words: []string{
 				"pizza", "burger", "taco", "pasta", "sushi", "donut", "sandwich", "salad",
 				"soup", "steak", "chicken", "fish", "rice", "noodles", "curry", "stew",
 				"bbq", "kebab", "wrap", "panini", "quesadilla", "burrito", "nachos",
@@ -240,8 +426,15 @@ func InitConfig() error {
 				"cheese", "olives", "pickles", "dips", "salsa", "guacamole", "bruschetta",
 				"canapes", "springrolls", "wings", "onionrings", "fries", "mozzarella",
 				"calamari", "samosas", "pakoras",
-			},
-			"animals": {
+			}, Emoji: map[string]string{
+				"pizza": "🍕", "burger": "🍔", "taco": "🌮", "pasta": "🍝", "sushi": "🍣",
+				"donut": "🍩", "sandwich": "🥪", "salad": "🥗", "soup": "🍲", "steak": "🥩",
+				"chicken": "🍗", "rice": "🍚", "curry": "🍛", "burrito": "🌯", "cake": "🍰",
+				"cupcake": "🧁", "cookie": "🍪", "pie": "🥧", "icecream": "🍨", "croissant": "🥐",
+				"candy": "🍬", "chocolate": "🍫", "coffee": "☕", "tea": "🍵", "boba": "🧋",
+				"popcorn": "🍿", "bread": "🍞", "cheese": "🧀", "fries": "🍟",
+			}},
+			"animals": {Description: "Animals and nature words", Order: 2, Words: []string{
 				"penguin", "koala", "dolphin", "eagle", "tiger", "panda", "turtle", "rabbit",
 				"fox", "wolf", "bear", "lion", "otter", "meerkat", "sloth", "hippo",
 				"giraffe", "zebra", "elephant", "rhino", "monkey", "gorilla", "orangutan",
@@ -261,8 +454,17 @@ func InitConfig() error {
 				"ant", "bee", "wasp", "hornet", "grasshopper", "cricket", "prayingmantis",
 				"spider", "scorpion", "centipede", "millipede", "earthworm", "leech",
 				"slug", "snail", "crayfish", "lobster", "crab", "shrimp", "prawn",
-			},
-			"pop": {
+			}, Emoji: map[string]string{
+				"penguin": "🐧", "koala": "🐨", "dolphin": "🐬", "eagle": "🦅", "tiger": "🐯",
+				"panda": "🐼", "turtle": "🐢", "rabbit": "🐰", "fox": "🦊", "wolf": "🐺",
+				"bear": "🐻", "lion": "🦁", "otter": "🦦", "sloth": "🦥", "hippo": "🦛",
+				"giraffe": "🦒", "zebra": "🦓", "elephant": "🐘", "monkey": "🐒", "kangaroo": "🦘",
+				"hedgehog": "🦔", "parrot": "🦜", "owl": "🦉", "flamingo": "🦩", "swan": "🦢",
+				"duck": "🦆", "peacock": "🦚", "shark": "🦈", "whale": "🐳", "octopus": "🐙",
+				"squid": "🦑", "butterfly": "🦋", "bee": "🐝", "ladybug": "🐞", "snail": "🐌",
+				"lobster": "🦞", "crab": "🦀", "shrimp": "🦐",
+			}},
+			"pop": {Description: "Pop culture, fantasy and creative arts words", Order: 3, Words: []string{
 				"ninja", "samurai", "wizard", "knight", "viking", "pirate", "astronaut",
 				"robot", "superhero", "detective", "warrior", "mage", "sorcerer", "paladin",
 				"ranger", "cleric", "druid", "assassin", "barbarian", "monk", "bard",
@@ -281,8 +483,8 @@ func InitConfig() error {
 				"contentcreator", "socialmedia", "tiktok", "instagram", "youtube",
 				"brandambassador", "spokesperson", "representative",
 				"ambassador", "diplomat", "negotiator", "mediator", "arbitrator",
-			},
-			"silly": {
+			}},
+			"silly": {Description: "Silly, funny and absurd words", Order: 4, Words: []string{
 				"potato", "banana", "unicorn", "noodle", "pickle", "muffin", "cupcake",
 				"cookie", "marshmallow", "popcorn", "cucumber", "broccoli", "carrot",
 				"tomato", "pepper", "onion", "garlic", "ginger", "lettuce", "spinach",
@@ -294,17 +496,22 @@ func InitConfig() error {
 				"calculator", "abacus", "typewriter", "telegraph", "telephone", "radio",
 				"television", "computer", "keyboard", "mouse", "monitor", "printer",
 				"scanner", "camera", "microphone", "speaker", "headphones", "earbuds",
+				"butterfly", "dragonfly", "firefly", "lightningbug", "ladybug",
+				"jellybean", "poprocks", "cottoncandy", "licorice", "taffy",
+				"gummybear", "chocolatechip", "peanutbutter", "strawberry", "blueberry",
+				"raspberry", "blackberry", "cranberry", "gooseberry", "elderberry",
+			}},
+			"adjectives": {Description: "Adjectives for composed names", Hidden: true, Pos: "adjective", Words: []string{
 				"happy", "sad", "angry", "excited", "nervous", "confused", "surprised",
 				"shocked", "amazed", "bored", "tired", "sleepy", "hungry", "thirsty",
 				"curious", "playful", "goofy", "weird", "strange", "bizarre", "odd",
 				"peculiar", "quirky", "crazy", "wild", "silly", "funny",
 				"hilarious", "comical", "absurd", "ridiculous", "ludicrous",
-				"butterfly", "dragonfly", "firefly", "lightningbug", "ladybug",
-				"jellybean", "poprocks", "cottoncandy", "licorice", "taffy",
-				"gummybear", "chocolatechip", "peanutbutter", "strawberry", "blueberry",
-				"raspberry", "blackberry", "cranberry", "gooseberry", "elderberry",
-			},
-			"dev": {
+				"brave", "calm", "clever", "cosmic", "dizzy", "eager", "fancy",
+				"fluffy", "fuzzy", "gentle", "grumpy", "jolly", "lazy", "lucky",
+				"mighty", "noisy", "sneaky", "sparkly", "speedy", "sunny", "witty",
+			}},
+			"dev": {Description: "Development tools and programming words", Order: 5, Words: []string{
 				"github", "gitlab", "bitbucket", "mercurial", "svn", "cvs", "perforce",
 				"stash", "source", "repository", "repo", "branch", "trunk", "tag",
 				"commit", "push", "pull", "merge", "rebase", "cherry-pick", "fork",
@@ -328,9 +535,7 @@ func InitConfig() error {
 				"mocha", "jasmine", "qunit", "vitest", "ava", "tape", "chai", "sinon",
 				"eslint", "prettier", "stylelint", "sonarqube", "codeclimate", "coveralls",
 				"codecov", "dependabot", "renovate", "snyk", "githubsecurity",
-			},
+			}},
 		},
 	}
-
-	return SaveConfig(defaultConfig)
 }
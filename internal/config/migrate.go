@@ -0,0 +1,462 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the schema version of the config files this version of
+// dir-init writes. Files without a version field are version 0.
+const CurrentVersion = 1
+
+// migration upgrades a config file from the previous version to version. It
+// works on the YAML tree, so comments and settings it does not know survive,
+// and returns a line for every change. When the file holds values the
+// migration cannot place without help, it leaves the tree as it is and returns
+// them as pending instead.
+type migration struct {
+	version int
+	apply   func(root *yaml.Node, stacks StackSides) (changes, pending []string, err error)
+}
+
+// migrations upgrade config files one version at a time, oldest first
+var migrations = []migration{
+	{version: 1, apply: migrateTechStacks},
+}
+
+// StackSides maps version 0 tech stack codes to "frontends" or "backends",
+// for stacks the migration cannot place on its own
+type StackSides map[string]string
+
+// SchemaMigration describes the upgrade of a config file to CurrentVersion
+type SchemaMigration struct {
+	From    int      // version of the file before the upgrade
+	To      int      // version reached, below CurrentVersion while Pending is not empty
+	Changes []string // one line per change, empty when the file is current
+	Pending []string // tech stacks that need a StackSides entry before the upgrade can finish
+	Backup  string   // copy of the file before the upgrade, empty for a dry run
+}
+
+// MigrateSchema upgrades the user config file to CurrentVersion, placing the
+// tech stacks the migration does not know by stacks. Loading a config only
+// upgrades it in memory, so this is what rewrites an older file. The file is
+// copied to config.yaml.v<version>-<time>.bak first; with dryRun, or while
+// values are pending, nothing is written and only the changes are returned.
+func (s *Store) MigrateSchema(dryRun bool, stacks StackSides) (*SchemaMigration, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.paths.Config
+	if dryRun {
+		// a dry run previews the files Migrate has not moved yet where they are
+		path = s.userPaths().Config
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &SchemaMigration{From: CurrentVersion, To: CurrentVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	format := FormatOf(path)
+	doc, err := parseDocument(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return &SchemaMigration{From: CurrentVersion, To: CurrentVersion}, nil
+	}
+
+	result, err := migrateNode(doc.Content[0], stacks)
+	if err != nil || len(result.Changes) == 0 || len(result.Pending) > 0 || dryRun {
+		return result, err
+	}
+	if format != FormatYAML && len(result.Changes) == 1 {
+		// JSON and TOML files are rewritten without their formatting and
+		// comments, so not just to add the version they read the same without
+		return &SchemaMigration{From: CurrentVersion, To: CurrentVersion}, nil
+	}
+
+	var out []byte
+	if format == FormatYAML {
+		out, err = marshalYAML(doc, yamlIndent(data))
+	} else {
+		out, err = marshalNode(doc, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	result.Backup = backupPath(path, result.From)
+	if err := os.WriteFile(result.Backup, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to back up config file: %w", err)
+	}
+	if err := writeConfigFile(path, out); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// backupPath names the copy of a config file of version made before it is upgraded
+func backupPath(path string, version int) string {
+	return fmt.Sprintf("%s.v%d-%s.bak", path, version, time.Now().Format("20060102-150405"))
+}
+
+// backupOlder copies a config file of an older version than CurrentVersion
+// before it is overwritten, returning the copy. Files that cannot be parsed
+// are copied too.
+func backupOlder(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	version := 0
+	if doc, err := parseDocument(data, FormatOf(path)); err == nil && len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		version, _ = nodeVersion(doc.Content[0])
+	}
+	if version >= CurrentVersion {
+		return "", nil
+	}
+
+	backup := backupPath(path, version)
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to back up config file: %w", err)
+	}
+	return backup, nil
+}
+
+// migrateNode upgrades the root mapping of a config file in place, as far as
+// it can without values pending. The version is only set to a version whose
+// migration finished, so a file with pending values is migrated again later.
+func migrateNode(root *yaml.Node, stacks StackSides) (*SchemaMigration, error) {
+	from, err := nodeVersion(root)
+	if err != nil {
+		return nil, err
+	}
+	if from > CurrentVersion {
+		return nil, fmt.Errorf("config version %d is newer than this dir-init supports (%d); upgrade dir-init", from, CurrentVersion)
+	}
+	result := &SchemaMigration{From: from, To: from}
+	if from == CurrentVersion {
+		return result, nil
+	}
+
+	// the comment at the top of the file belongs to its first key, which a
+	// migration may move or remove
+	var top string
+	if len(root.Content) > 0 {
+		top, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	defer func() {
+		if len(root.Content) > 0 {
+			root.Content[0].HeadComment = top
+		}
+	}()
+
+	for _, m := range migrations {
+		if m.version <= from {
+			continue
+		}
+		changes, pending, err := m.apply(root, stacks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate config to version %d: %w", m.version, err)
+		}
+		if len(pending) > 0 {
+			result.Pending = pending
+			break
+		}
+		result.Changes = append(result.Changes, changes...)
+		result.To = m.version
+	}
+
+	if result.To > from {
+		setVersion(root, result.To)
+		result.Changes = append(result.Changes, fmt.Sprintf("set version to %d", result.To))
+	}
+	return result, nil
+}
+
+// nodeVersion returns the version field of a config file, 0 when it has none
+func nodeVersion(root *yaml.Node) (int, error) {
+	version := 0
+	if i := mappingIndex(root, "version"); i >= 0 {
+		if err := root.Content[i+1].Decode(&version); err != nil || version < 0 {
+			return 0, fmt.Errorf("line %d: version must be a whole number", root.Content[i+1].Line)
+		}
+	}
+	return version, nil
+}
+
+// setVersion sets the version field, adding it as the first key
+func setVersion(root *yaml.Node, version int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: fmt.Sprint(version)}
+	if i := mappingIndex(root, "version"); i >= 0 {
+		root.Content[i+1] = value
+		return
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// legacyStack is where the entries of a version 0 tech stack belong
+type legacyStack struct {
+	side string // "frontends" or "backends"
+	code string // code of the stack as a frontend or backend, when it has no frameworks
+}
+
+// legacyStacks places the tech stacks of version 0 files: those of the old
+// documentation, such as "fejs", and the languages and platforms used as
+// stack codes
+var legacyStacks = map[string]legacyStack{
+	"fe": {"frontends", "fe"}, "frontend": {"frontends", "frontend"},
+	"fejs": {"frontends", "js"}, "fets": {"frontends", "ts"},
+	"js": {"frontends", "js"}, "javascript": {"frontends", "js"},
+	"ts": {"frontends", "ts"}, "typescript": {"frontends", "ts"},
+	"web": {"frontends", "web"}, "ui": {"frontends", "ui"},
+	"html": {"frontends", "html"}, "css": {"frontends", "html"},
+
+	"be": {"backends", "be"}, "backend": {"backends", "backend"},
+	"bejs": {"backends", "node"}, "bets": {"backends", "node"},
+	"bepy": {"backends", "py"}, "bego": {"backends", "go"},
+	"bejava": {"backends", "java"}, "berb": {"backends", "ruby"},
+	"bephp": {"backends", "php"}, "bers": {"backends", "rust"},
+	"node": {"backends", "node"}, "nodejs": {"backends", "node"},
+	"py": {"backends", "py"}, "python": {"backends", "py"},
+	"go": {"backends", "go"}, "golang": {"backends", "go"},
+	"java": {"backends", "java"}, "kotlin": {"backends", "kotlin"},
+	"ruby": {"backends", "ruby"}, "rb": {"backends", "ruby"},
+	"php": {"backends", "php"}, "rust": {"backends", "rust"},
+	"rs": {"backends", "rust"}, "cs": {"backends", "csharp"},
+	"csharp": {"backends", "csharp"}, "dotnet": {"backends", "csharp"},
+	"elixir": {"backends", "elixir"}, "scala": {"backends", "scala"},
+	"deno": {"backends", "deno"}, "bun": {"backends", "bun"},
+}
+
+// legacyFrameworks places the well-known frameworks of version 0 files
+// whatever stack they are listed under, so that Next.js under a "js" stack
+// still becomes a frontend and Express a backend
+var legacyFrameworks = map[string]string{
+	"react": "frontends", "rct": "frontends", "vue": "frontends",
+	"angular": "frontends", "ng": "frontends", "svelte": "frontends",
+	"sveltekit": "frontends", "next": "frontends", "nextjs": "frontends",
+	"nxt": "frontends", "nuxt": "frontends", "solid": "frontends",
+	"sol": "frontends", "qwik": "frontends", "preact": "frontends",
+	"pre": "frontends", "gatsby": "frontends", "grt": "frontends",
+	"astro": "frontends", "remix": "frontends", "ember": "frontends",
+	"lit": "frontends", "alpine": "frontends", "jquery": "frontends",
+
+	"express": "backends", "nest": "backends", "nestjs": "backends",
+	"koa": "backends", "fastify": "backends", "hapi": "backends",
+	"django": "backends", "flask": "backends", "fastapi": "backends",
+	"rails": "backends", "sinatra": "backends", "laravel": "backends",
+	"symfony": "backends", "spring": "backends", "springboot": "backends",
+	"gin": "backends", "echo": "backends", "fiber": "backends",
+	"chi": "backends", "actix": "backends", "axum": "backends",
+	"rocket": "backends", "phoenix": "backends", "aspnet": "backends",
+}
+
+// ParseStackSide accepts "frontend", "frontends", "backend" or "backends" and
+// returns the config key it stands for
+func ParseStackSide(side string) (string, error) {
+	switch strings.ToLower(side) {
+	case "frontend", "frontends", "fe":
+		return "frontends", nil
+	case "backend", "backends", "be":
+		return "backends", nil
+	}
+	return "", fmt.Errorf("unknown side '%s' (use frontend or backend)", side)
+}
+
+// migrateTechStacks moves the tech_stacks and frameworks of version 0 to
+// frontends and backends. Well-known frameworks go to their side, the other
+// frameworks of a stack to the side of the stack, and a stack without
+// frameworks becomes a frontend or backend itself, "bego" becoming "go".
+// Stacks are placed by stacks, then by legacyStacks. When a stack or one of
+// its frameworks cannot be placed, nothing is moved and the stack is pending.
+func migrateTechStacks(root *yaml.Node, stacks StackSides) ([]string, []string, error) {
+	var legacy struct {
+		TechStacks []TechStack            `yaml:"tech_stacks"`
+		Frameworks map[string][]Framework `yaml:"frameworks"`
+		Frontends  []Frontend             `yaml:"frontends"`
+		Backends   []Backend              `yaml:"backends"`
+	}
+	// merge directives such as frontends: !remove [ng] are not values
+	if err := withoutDirectives(root).Decode(&legacy); err != nil {
+		return nil, nil, err
+	}
+	if len(legacy.TechStacks) == 0 && len(legacy.Frameworks) == 0 {
+		return nil, nil, nil
+	}
+
+	// stacks in file order, then those only named in frameworks
+	order := append([]TechStack(nil), legacy.TechStacks...)
+	listed := make(map[string]bool)
+	for _, ts := range legacy.TechStacks {
+		listed[ts.Code] = true
+	}
+	var unlisted []string
+	for stack := range legacy.Frameworks {
+		if !listed[stack] {
+			unlisted = append(unlisted, stack)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, stack := range unlisted {
+		order = append(order, TechStack{Code: stack})
+	}
+
+	have := map[string]map[string]bool{"frontends": {}, "backends": {}}
+	for _, fe := range legacy.Frontends {
+		have["frontends"][fe.Code] = true
+	}
+	for _, be := range legacy.Backends {
+		have["backends"][be.Code] = true
+	}
+
+	var changes, pending []string
+	var frontends []Frontend
+	var backends []Backend
+	add := func(side, code, description, source string) {
+		if have[side][code] {
+			changes = append(changes, fmt.Sprintf("dropped %s: '%s' is already in %s", source, code, side))
+			return
+		}
+		have[side][code] = true
+		if side == "frontends" {
+			frontends = append(frontends, Frontend{Code: code, Description: description})
+		} else {
+			backends = append(backends, Backend{Code: code, Description: description})
+		}
+		changes = append(changes, fmt.Sprintf("moved %s to %s as '%s'", source, side, code))
+	}
+
+	for _, ts := range order {
+		stack, known := legacyStacks[strings.ToLower(ts.Code)]
+		if side, ok := stacks[ts.Code]; ok {
+			if side != stack.side {
+				stack.code = ts.Code
+			}
+			stack.side, known = side, true
+		}
+
+		fws := legacy.Frameworks[ts.Code]
+		if len(fws) == 0 {
+			if !known {
+				pending = append(pending, ts.Code)
+				continue
+			}
+			add(stack.side, stack.code, ts.Description, fmt.Sprintf("tech stack '%s'", ts.Code))
+			continue
+		}
+
+		placed := true
+		for _, fw := range fws {
+			side := legacyFrameworks[strings.ToLower(fw.Code)]
+			if side == "" {
+				side = stack.side
+			}
+			if side == "" {
+				placed = false
+				continue
+			}
+			add(side, fw.Code, fw.Description, fmt.Sprintf("framework '%s' of tech stack '%s'", fw.Code, ts.Code))
+		}
+		if !placed {
+			pending = append(pending, ts.Code)
+		}
+	}
+	if len(pending) > 0 {
+		return nil, pending, nil
+	}
+
+	// the new lists take the place of tech_stacks, unless the file has them already
+	at := mappingIndex(root, "tech_stacks")
+	if at < 0 {
+		at = mappingIndex(root, "frameworks")
+	}
+	deleteMappingKey(root, "tech_stacks")
+	deleteMappingKey(root, "frameworks")
+	for _, list := range []struct {
+		key   string
+		items interface{}
+		n     int
+	}{{"frontends", frontends, len(frontends)}, {"backends", backends, len(backends)}} {
+		if list.n == 0 {
+			continue
+		}
+		if mappingIndex(root, list.key) < 0 {
+			if err := insertMappingValue(root, at, list.key, list.items); err != nil {
+				return nil, nil, err
+			}
+			at += 2
+			continue
+		}
+		if err := appendToSequence(root, list.key, list.items); err != nil {
+			return nil, nil, err
+		}
+	}
+	return changes, nil, nil
+}
+
+// appendToSequence appends the items of a slice to the list under key, adding the key if needed
+func appendToSequence(root *yaml.Node, key string, items interface{}) error {
+	var list yaml.Node
+	if err := list.Encode(items); err != nil {
+		return err
+	}
+	if i := mappingIndex(root, key); i >= 0 && root.Content[i+1].Kind == yaml.SequenceNode {
+		root.Content[i+1].Content = append(root.Content[i+1].Content, list.Content...)
+		return nil
+	}
+	return setMappingValue(root, key, items)
+}
+
+// setMappingValue sets key to value, adding the key if needed
+func setMappingValue(root *yaml.Node, key string, value interface{}) error {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return err
+	}
+	if i := mappingIndex(root, key); i >= 0 {
+		root.Content[i+1] = &node
+		return nil
+	}
+	root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &node)
+	return nil
+}
+
+// insertMappingValue adds key with value at index at of the mapping, or at its end for -1
+func insertMappingValue(root *yaml.Node, at int, key string, value interface{}) error {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return err
+	}
+	if at < 0 || at > len(root.Content) {
+		at = len(root.Content)
+	}
+	entry := []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &node}
+	root.Content = append(root.Content[:at], append(entry, root.Content[at:]...)...)
+	return nil
+}
+
+// deleteMappingKey removes key and its value
+func deleteMappingKey(root *yaml.Node, key string) {
+	if i := mappingIndex(root, key); i >= 0 {
+		root.Content = append(root.Content[:i], root.Content[i+2:]...)
+	}
+}
@@ -0,0 +1,273 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateNode(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		stacks      StackSides
+		want        string // the file after the migration
+		wantTo      int
+		wantPending []string
+		wantErr     string
+	}{
+		{
+			name:   "current file",
+			file:   "version: 1\ncase: snake\n",
+			want:   "version: 1\ncase: snake\n",
+			wantTo: 1,
+		},
+		{
+			name:   "file without stacks",
+			file:   "# my config\ncase: snake\n",
+			want:   "# my config\nversion: 1\ncase: snake\n",
+			wantTo: 1,
+		},
+		{
+			name: "stacks and frameworks of the mapping table",
+			file: `case: snake
+tech_stacks:
+  - code: fejs
+    description: JavaScript frontend
+  - code: bepy
+    description: Python backend
+frameworks:
+  fejs:
+    - code: react
+      description: React
+layout: "{word}{suffix}"
+`,
+			want: `version: 1
+case: snake
+frontends:
+  - code: react
+    description: React
+backends:
+  - code: py
+    description: Python backend
+layout: "{word}{suffix}"
+`,
+			wantTo: 1,
+		},
+		{
+			name: "well-known frameworks go to their side",
+			file: `tech_stacks:
+  - code: js
+frameworks:
+  js:
+    - code: next
+    - code: express
+`,
+			want: `version: 1
+frontends:
+  - code: next
+    description: ""
+backends:
+  - code: express
+    description: ""
+`,
+			wantTo: 1,
+		},
+		{
+			name: "entries already listed are kept once",
+			file: `frontends:
+  - code: js
+    description: JavaScript
+tech_stacks:
+  - code: javascript
+    description: Old JavaScript
+`,
+			want: `version: 1
+frontends:
+  - code: js
+    description: JavaScript
+`,
+			wantTo: 1,
+		},
+		{
+			name: "unknown stacks are pending and nothing changes",
+			file: `tech_stacks:
+  - code: mobile
+  - code: bepy
+`,
+			want: `tech_stacks:
+  - code: mobile
+  - code: bepy
+`,
+			wantPending: []string{"mobile"},
+		},
+		{
+			name: "unknown frameworks of unknown stacks are pending",
+			file: `frameworks:
+  mystack:
+    - code: react
+    - code: homegrown
+`,
+			want: `frameworks:
+  mystack:
+    - code: react
+    - code: homegrown
+`,
+			wantPending: []string{"mystack"},
+		},
+		{
+			name: "stack sides place unknown stacks",
+			file: `tech_stacks:
+  - code: mobile
+    description: Mobile apps
+`,
+			stacks: StackSides{"mobile": "frontends"},
+			want: `version: 1
+frontends:
+  - code: mobile
+    description: Mobile apps
+`,
+			wantTo: 1,
+		},
+		{
+			name: "stack sides override the mapping table",
+			file: `tech_stacks:
+  - code: py
+`,
+			stacks: StackSides{"py": "frontends"},
+			want: `version: 1
+frontends:
+  - code: py
+    description: ""
+`,
+			wantTo: 1,
+		},
+		{
+			name:    "newer version",
+			file:    "version: 9\n",
+			wantErr: "config version 9 is newer",
+		},
+		{
+			name:    "invalid version",
+			file:    "version: one\n",
+			wantErr: "line 1: version must be a whole number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.file), &doc); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := migrateNode(doc.Content[0], tt.stacks)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("migrateNode() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("migrateNode: %v", err)
+			}
+
+			if result.To != tt.wantTo {
+				t.Errorf("To = %d, want %d", result.To, tt.wantTo)
+			}
+			if !reflect.DeepEqual(result.Pending, tt.wantPending) {
+				t.Errorf("Pending = %v, want %v", result.Pending, tt.wantPending)
+			}
+			out, err := marshalYAML(&doc, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("migrated file:\n%s\nwant:\n%s", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		dryRun     bool
+		stacks     StackSides
+		wantFile   string // "" when the file must stay as it is
+		wantBackup bool
+	}{
+		{
+			name:       "upgrade with a backup",
+			file:       "tech_stacks:\n    - code: bego\n",
+			wantFile:   "version: 1\nbackends:\n    - code: go\n      description: \"\"\n",
+			wantBackup: true,
+		},
+		{
+			name:   "dry run",
+			file:   "tech_stacks:\n  - code: bego\n",
+			dryRun: true,
+		},
+		{
+			name: "pending stacks",
+			file: "tech_stacks:\n  - code: mobile\n",
+		},
+		{
+			name:       "pending stacks given a side",
+			file:       "tech_stacks:\n  - code: mobile\n",
+			stacks:     StackSides{"mobile": "backends"},
+			wantFile:   "version: 1\nbackends:\n  - code: mobile\n    description: \"\"\n",
+			wantBackup: true,
+		},
+		{
+			name: "current file",
+			file: "version: 1\ncase: snake\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := NewStore(Paths{Config: path}).MigrateSchema(tt.dryRun, tt.stacks)
+			if err != nil {
+				t.Fatalf("MigrateSchema: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.wantFile
+			if want == "" {
+				want = tt.file
+			}
+			if string(data) != want {
+				t.Errorf("config file:\n%s\nwant:\n%s", data, want)
+			}
+
+			backups, _ := filepath.Glob(filepath.Join(dir, "*.bak"))
+			if tt.wantBackup != (len(backups) == 1) || tt.wantBackup != (result.Backup != "") {
+				t.Fatalf("backups = %v, Backup = %q, want a backup: %v", backups, result.Backup, tt.wantBackup)
+			}
+			if tt.wantBackup {
+				backup, err := os.ReadFile(result.Backup)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(backup) != tt.file {
+					t.Errorf("backup = %q, want %q", backup, tt.file)
+				}
+			}
+		})
+	}
+}
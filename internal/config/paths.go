@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ConfigEnv names a config file to use instead of the default location
+const ConfigEnv = "DIR_INIT_CONFIG"
+
+// Paths are the files and directories a Store reads and writes
+type Paths struct {
+	Config   string // config file
+	Words    string // directory of plain-text word lists, one file per category
+	Sessions string // directory where run sessions are recorded for replay
+
+	// Legacy is the ~/.dir-init directory of older versions, whose files
+	// Migrate moves to the paths above. Empty for an explicit config file.
+	Legacy string
+}
+
+// DefaultPaths follows the XDG base directory specification:
+//
+//	$XDG_CONFIG_HOME/dir-init/config.yaml  (~/.config/dir-init/config.yaml)
+//	$XDG_DATA_HOME/dir-init/words          (~/.local/share/dir-init/words)
+//	$XDG_STATE_HOME/dir-init/sessions      (~/.local/state/dir-init/sessions)
+func DefaultPaths() (Paths, error) {
+	configDir, err := xdgDir("XDG_CONFIG_HOME", ".config")
+	if err != nil {
+		return Paths{}, err
+	}
+	dataDir, err := xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	if err != nil {
+		return Paths{}, err
+	}
+	stateDir, err := xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+	if err != nil {
+		return Paths{}, err
+	}
+
+	paths := Paths{
+		Config:   filepath.Join(configDir, "config.yaml"),
+		Words:    filepath.Join(dataDir, "words"),
+		Sessions: filepath.Join(stateDir, "sessions"),
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths.Legacy = filepath.Join(home, ".dir-init")
+	}
+	return paths, nil
+}
+
+// FilePaths uses an explicit config file, with its word lists in a words
+// directory next to it. Sessions stay in the XDG state directory, or next to
+// the file when there is no home directory.
+func FilePaths(path string) Paths {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	paths := Paths{
+		Config:   path,
+		Words:    filepath.Join(filepath.Dir(path), "words"),
+		Sessions: filepath.Join(filepath.Dir(path), "sessions"),
+	}
+	if stateDir, err := xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state")); err == nil {
+		paths.Sessions = filepath.Join(stateDir, "sessions")
+	}
+	return paths
+}
+
+// xdgDir returns the dir-init directory below an XDG base directory. Relative
+// values of the variable are ignored, as the specification requires.
+func xdgDir(env, fallback string) (string, error) {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "dir-init"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate the config directory: %w (set %s, or %s or --config)", err, env, ConfigEnv)
+	}
+	return filepath.Join(home, fallback, "dir-init"), nil
+}
+
+// Migrate moves the config file, word lists and sessions of a ~/.dir-init
+// directory to their XDG locations, skipping those that already exist there,
+// and removes the directory once it is empty. It returns a "from -> to" line
+// for every move.
+func (s *Store) Migrate() ([]string, error) {
+	if s.err != nil || s.paths.Legacy == "" {
+		return nil, s.err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	moves := []struct{ from, to string }{
+		{filepath.Join(s.paths.Legacy, "config.yaml"), s.paths.Config},
+		{filepath.Join(s.paths.Legacy, "words"), s.paths.Words},
+		{filepath.Join(s.paths.Legacy, "sessions"), s.paths.Sessions},
+	}
+
+	var moved []string
+	for _, m := range moves {
+		if _, err := os.Stat(m.from); err != nil {
+			continue
+		}
+		if _, err := os.Stat(m.to); err == nil {
+			continue
+		}
+		if err := move(m.from, m.to); err != nil {
+			return moved, fmt.Errorf("failed to migrate %s: %w", m.from, err)
+		}
+		moved = append(moved, m.from+" -> "+m.to)
+	}
+
+	os.Remove(s.paths.Legacy) // only succeeds once nothing is left
+	return moved, nil
+}
+
+// move renames a file or directory, copying it when it crosses file systems
+func move(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+
+	err := filepath.WalkDir(from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return copyFile(path, target)
+	})
+	if err != nil {
+		os.RemoveAll(to)
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
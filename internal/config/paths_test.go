@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string // XDG_CONFIG_HOME
+		data   string // XDG_DATA_HOME
+		state  string // XDG_STATE_HOME
+		want   Paths
+	}{
+		{
+			name: "home directory",
+			want: Paths{
+				Config:   "/home/me/.config/dir-init/config.yaml",
+				Words:    "/home/me/.local/share/dir-init/words",
+				Sessions: "/home/me/.local/state/dir-init/sessions",
+				Legacy:   "/home/me/.dir-init",
+			},
+		},
+		{
+			name:   "XDG variables",
+			config: "/xdg/config",
+			data:   "/xdg/data",
+			state:  "/xdg/state",
+			want: Paths{
+				Config:   "/xdg/config/dir-init/config.yaml",
+				Words:    "/xdg/data/dir-init/words",
+				Sessions: "/xdg/state/dir-init/sessions",
+				Legacy:   "/home/me/.dir-init",
+			},
+		},
+		{
+			name:   "relative XDG variables are ignored",
+			config: "relative/config",
+			want: Paths{
+				Config:   "/home/me/.config/dir-init/config.yaml",
+				Words:    "/home/me/.local/share/dir-init/words",
+				Sessions: "/home/me/.local/state/dir-init/sessions",
+				Legacy:   "/home/me/.dir-init",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", "/home/me")
+			t.Setenv("XDG_CONFIG_HOME", tt.config)
+			t.Setenv("XDG_DATA_HOME", tt.data)
+			t.Setenv("XDG_STATE_HOME", tt.state)
+
+			got, err := DefaultPaths()
+			if err != nil {
+				t.Fatalf("DefaultPaths: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DefaultPaths() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegacyFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		legacy    map[string]string // files of ~/.dir-init
+		current   map[string]string // files already at the XDG locations
+		wantCase  string            // case of the loaded config
+		wantMoved []string          // files moved by Migrate, relative to the legacy directory
+		wantKept  bool              // the legacy directory is left behind
+	}{
+		{
+			name:      "legacy files only",
+			legacy:    map[string]string{"config.yaml": "case: snake\n", "words/food.txt": "pizza\n"},
+			wantCase:  "snake",
+			wantMoved: []string{"config.yaml", "words"},
+		},
+		{
+			name:      "newer files win",
+			legacy:    map[string]string{"config.yaml": "case: snake\n", "words/food.txt": "pizza\n"},
+			current:   map[string]string{"config/config.yaml": "case: kebab\n"},
+			wantCase:  "kebab",
+			wantMoved: []string{"words"},
+			wantKept:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			paths := Paths{
+				Config:   filepath.Join(dir, "config", "config.yaml"),
+				Words:    filepath.Join(dir, "data", "words"),
+				Sessions: filepath.Join(dir, "state", "sessions"),
+				Legacy:   filepath.Join(dir, ".dir-init"),
+			}
+			for name, content := range tt.legacy {
+				writeTestFile(t, filepath.Join(paths.Legacy, name), content)
+			}
+			for name, content := range tt.current {
+				writeTestFile(t, filepath.Join(dir, name), content)
+			}
+			saved := systemConfigPath
+			systemConfigPath = filepath.Join(dir, "etc", "config.yaml")
+			t.Cleanup(func() { systemConfigPath = saved })
+			t.Chdir(dir)
+
+			s := NewStore(paths)
+			before := listFiles(t, dir)
+			cfg, err := s.LoadLayered()
+			if err != nil {
+				t.Fatalf("LoadLayered: %v", err)
+			}
+			if cfg.Case != tt.wantCase {
+				t.Errorf("case = %q, want %q", cfg.Case, tt.wantCase)
+			}
+			if words := cfg.Categories["food"].Words; !reflect.DeepEqual(words, []string{"pizza"}) {
+				t.Errorf("words of food = %v, want [pizza]", words)
+			}
+			if after := listFiles(t, dir); !reflect.DeepEqual(after, before) {
+				t.Errorf("LoadLayered() moved files: %v, before %v", after, before)
+			}
+
+			moved, err := s.Migrate()
+			if err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			var want []string
+			targets := map[string]string{"config.yaml": paths.Config, "words": paths.Words}
+			for _, name := range tt.wantMoved {
+				want = append(want, filepath.Join(paths.Legacy, name)+" -> "+targets[name])
+			}
+			if !reflect.DeepEqual(moved, want) {
+				t.Errorf("Migrate() = %v, want %v", moved, want)
+			}
+			if _, err := os.Stat(paths.Legacy); (err == nil) != tt.wantKept {
+				t.Errorf("legacy directory left behind: %v, want %v", err == nil, tt.wantKept)
+			}
+			if _, err := os.Stat(filepath.Join(paths.Words, "food.txt")); err != nil {
+				t.Errorf("word list was not moved: %v", err)
+			}
+		})
+	}
+}
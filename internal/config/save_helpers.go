@@ -4,7 +4,12 @@ import "fmt"
 
 // SaveFrontend adds a frontend to the config
 func SaveFrontend(code, description string) error {
-	config, err := LoadConfig()
+	return Default().SaveFrontend(code, description)
+}
+
+// SaveFrontend adds a frontend to the user config file of the store
+func (s *Store) SaveFrontend(code, description string) error {
+	config, err := s.LoadUserConfig()
 	if err != nil {
 		return err
 	}
@@ -22,12 +27,17 @@ func SaveFrontend(code, description string) error {
 		Description: description,
 	})
 
-	return SaveConfig(config)
+	return s.SaveConfig(config)
 }
 
 // SaveBackend adds a backend to the config
 func SaveBackend(code, description string) error {
-	config, err := LoadConfig()
+	return Default().SaveBackend(code, description)
+}
+
+// SaveBackend adds a backend to the user config file of the store
+func (s *Store) SaveBackend(code, description string) error {
+	config, err := s.LoadUserConfig()
 	if err != nil {
 		return err
 	}
@@ -45,5 +55,5 @@ func SaveBackend(code, description string) error {
 		Description: description,
 	})
 
-	return SaveConfig(config)
+	return s.SaveConfig(config)
 }
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// schemaNotes adds descriptions and allowed values to the properties of the
+// JSON Schema, keyed by Go type and YAML key
+var schemaNotes = map[string]map[string]interface{}{
+	"Config.version":         {"description": "Schema version of the file, written by dir-init", "minimum": 0, "maximum": CurrentVersion},
+	"Config.tech_stacks":     {"description": "Version 0 model of frontends and backends, moved by 'dir-init config migrate'", "deprecated": true},
+	"Config.frameworks":      {"description": "Version 0 frameworks of each tech stack, moved by 'dir-init config migrate'", "deprecated": true},
+	"Config.categories":      {"description": "Word lists by category name"},
+	"Config.frontends":       {"description": "Frontend codes offered by interactive mode"},
+	"Config.backends":        {"description": "Backend codes offered by interactive mode"},
+	"Config.layout":          {"description": "Name layout, such as \"{frontend}-{backend}-{word}{suffix}\""},
+	"Config.sampling":        {"description": "How categories and words are picked", "enum": []string{"uniform-category", "uniform-word", "weighted"}},
+	"Config.compose":         {"description": "Word composition pattern, such as \"adj-noun\""},
+	"Config.case":            {"description": "Case style of names", "enum": []string{"none", "kebab", "snake", "camel", "pascal", "dot", "upper"}},
+	"Config.separator":       {"description": "Separator between name parts, overriding the case style default"},
+	"Config.max_length":      {"description": "Longest generated name, 0 for unlimited", "minimum": 0},
+	"Config.max_word_length": {"description": "Words longer than this are skipped", "minimum": 0},
+	"Config.max_code_length": {"description": "Longer frontend and backend codes are abbreviated", "minimum": 0},
+	"Config.suffix_digits":   {"description": "Trailing digits of pronounceable suffixes", "minimum": 0},
+	"Config.blocklist":       {"description": "Words and substrings generated names must not contain"},
+	"Config.safe_for_work":   {"description": "Built-in safe for work filter, on unless false"},
+	"Config.emojis":          {"description": "Prefix names with the emoji of their word"},
+	"Config.include_numbers": {"description": "Digits in suffixes, on unless false"},
+	"Config.portability":     {"description": "Characters names may use", "enum": []string{"native", "ascii", "posix"}},
+	"Config.alliterate":      {"description": "Pick words starting with the initial of the frontend or backend", "enum": []string{"any", "frontend", "backend", "both"}},
+
+	"Frontend.code":        {"description": "Short code used in names, such as \"rct\""},
+	"Frontend.description": {"description": "Shown when choosing a frontend"},
+	"Backend.code":         {"description": "Short code used in names, such as \"node\""},
+	"Backend.description":  {"description": "Shown when choosing a backend"},
+
+	"Category.description":  {"description": "Shown by 'dir-init categories'"},
+	"Category.examples":     {"description": "Words shown instead of the first words of the category"},
+	"Category.hidden":       {"description": "Leave the category out of listings and of \"all\""},
+	"Category.order":        {"description": "Listing position, lower first; unordered categories come last", "minimum": 0},
+	"Category.pos":          {"description": "Part of speech used by composition patterns", "enum": []string{"adjective", "noun", "verb"}},
+	"Category.weight":       {"description": "Sampling weight of the category", "minimum": 0},
+	"Category.words":        {"description": "Letters and digits, optionally joined by hyphens"},
+	"Category.word_weights": {"description": "Sampling weight of single words"},
+	"Category.emoji":        {"description": "Emoji of single words, shown with --emoji"},
+
+	"Blocklist.words":      {"description": "Whole words, matched ignoring case"},
+	"Blocklist.substrings": {"description": "Matched anywhere in words and suffixes"},
+}
+
+// JSONSchema returns a JSON Schema of the config file, for editors that
+// complete and check YAML files against a schema
+func JSONSchema() ([]byte, error) {
+	schema := objectSchema(reflect.TypeOf(Config{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "dir-init config"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// typeSchema returns the schema of a Go type of the config
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t {
+	case reflect.TypeOf(Category{}):
+		// a plain list of words, or a mapping with settings and metadata
+		return map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				objectSchema(t),
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Int:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return objectSchema(t)
+	}
+	return map[string]interface{}{}
+}
+
+// objectSchema returns the schema of a struct, one property per YAML key
+func objectSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || key == "" || key == "-" {
+			continue
+		}
+
+		property := typeSchema(field.Type)
+		for name, value := range schemaNotes[t.Name()+"."+key] {
+			property[name] = value
+		}
+		properties[key] = property
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if _, ok := properties["code"]; ok {
+		schema["required"] = []string{"code"}
+	}
+	return schema
+}
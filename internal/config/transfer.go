@@ -0,0 +1,346 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Import strategies, deciding how the values of an imported file combine with
+// those of the config
+const (
+	ImportMerge        = "merge"         // like a higher config layer: lists are extended, other values replaced
+	ImportReplace      = "replace"       // the sections and categories of the file replace those of the config
+	ImportSkipExisting = "skip-existing" // only entries and values the config does not have are added
+)
+
+// ImportStrategies lists the import strategies
+var ImportStrategies = []string{ImportMerge, ImportReplace, ImportSkipExisting}
+
+// SectionSettings selects the single-valued settings, such as case and layout, for Export
+const SectionSettings = "settings"
+
+// Export returns the config as a file of format. When sections are given,
+// only those are exported: top-level keys such as "categories" and
+// "frontends", "settings" for the single-valued settings, or
+// "categories.<name>" for one category.
+func (c *Config) Export(format string, sections ...string) ([]byte, error) {
+	file := *c
+	file.Version = CurrentVersion
+
+	var root yaml.Node
+	if err := root.Encode(&file); err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if len(sections) > 0 {
+		selected, err := selectSections(&root, sections)
+		if err != nil {
+			return nil, err
+		}
+		root = *selected
+	}
+	return marshalNode(&root, format)
+}
+
+// selectSections returns the version and the given sections of a config tree,
+// in the order of the tree
+func selectSections(root *yaml.Node, sections []string) (*yaml.Node, error) {
+	known := yamlKeys(reflect.TypeOf(Config{}))
+	settings := make(map[string]bool)
+	for _, key := range settingKeys() {
+		settings[key] = true
+	}
+
+	keys := map[string]bool{"version": true}
+	categories := make(map[string]bool)
+	for _, section := range sections {
+		name, category, isCategory := strings.Cut(section, ".")
+		switch {
+		case section == SectionSettings:
+			for key := range settings {
+				keys[key] = true
+			}
+		case isCategory && name == "categories":
+			i := mappingIndex(root, "categories")
+			if i < 0 || mappingIndex(root.Content[i+1], category) < 0 {
+				return nil, fmt.Errorf("category '%s' not found", category)
+			}
+			categories[category] = true
+		case known[section]:
+			keys[section] = true
+		default:
+			return nil, fmt.Errorf("unknown section '%s' (use a top-level key such as categories or frontends, settings, or categories.<name>)", section)
+		}
+	}
+
+	selected := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch {
+		case keys[key.Value]:
+			selected.Content = append(selected.Content, key, value)
+		case key.Value == "categories" && len(categories) > 0:
+			picked := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				if categories[value.Content[j].Value] {
+					picked.Content = append(picked.Content, value.Content[j], value.Content[j+1])
+				}
+			}
+			selected.Content = append(selected.Content, key, picked)
+		}
+	}
+	return selected, nil
+}
+
+// Change is a difference an import makes to the config
+type Change struct {
+	Op    string // "+" added, "-" removed, "~" changed
+	Path  string // key of the value, such as "case", "frontends" or "categories.food.words"
+	Value string // the added or removed entries, or "old -> new"
+}
+
+// String formats the change as "+ categories.food.words: pizza, taco"
+func (c Change) String() string {
+	if c.Value == "" {
+		return c.Op + " " + c.Path
+	}
+	return fmt.Sprintf("%s %s: %s", c.Op, c.Path, c.Value)
+}
+
+// Import combines the config file at path, in YAML, JSON or TOML by its
+// extension, with the config using strategy. It returns the combined config
+// and the changes it has over c, which is left unchanged; save the result with
+// SaveConfig. Files with errors that 'config validate' reports are refused.
+func (c *Config) Import(path, strategy string) (*Config, []Change, error) {
+	switch strategy {
+	case ImportMerge, ImportReplace, ImportSkipExisting:
+	default:
+		return nil, nil, fmt.Errorf("unknown import strategy '%s' (use merge, replace or skip-existing)", strategy)
+	}
+
+	layer, err := readFileLayer("import", path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var errs []string
+	for _, p := range checkConfigFile(path) {
+		if !p.Warning {
+			errs = append(errs, p.String())
+		}
+	}
+	if len(errs) > 0 {
+		return nil, nil, fmt.Errorf("cannot import %s:\n%s", path, strings.Join(errs, "\n"))
+	}
+
+	src := layer.node
+	normalizeLayer(src)
+
+	var dst yaml.Node
+	if err := dst.Encode(c); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	normalizeLayer(&dst)
+
+	m := &merger{origins: make(map[*yaml.Node]*Layer)}
+	before := m.clone(&dst, &layer)
+	switch strategy {
+	case ImportMerge:
+		m.merge(&dst, src, &layer)
+	case ImportReplace:
+		markReplaced(src)
+		m.merge(&dst, src, &layer)
+	case ImportSkipExisting:
+		m.fill(&dst, src, &layer)
+	}
+
+	var result Config
+	if err := dst.Decode(&result); err != nil {
+		return nil, nil, fmt.Errorf("failed to import %s: %w", path, err)
+	}
+	if result.Frameworks == nil {
+		result.Frameworks = make(map[string][]Framework)
+	}
+	if result.Categories == nil {
+		result.Categories = make(map[string]Category)
+	}
+
+	// words of word list files stay in their files when the result is saved
+	for name, cat := range c.Categories {
+		if imported, ok := result.Categories[name]; ok {
+			imported.wordFiles, imported.fileOnly = cat.wordFiles, cat.fileOnly
+			result.Categories[name] = imported
+		}
+	}
+
+	return &result, diffNodes(nil, "", before, &dst), nil
+}
+
+// markReplaced tags the top-level values and the categories of an imported
+// file with !replace, so that merging replaces them instead of merging into
+// them. Values tagged !remove keep their tag.
+func markReplaced(root *yaml.Node) {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		value := root.Content[i+1]
+		if value.Tag == tagRemove {
+			continue
+		}
+		if root.Content[i].Value == "categories" && value.Kind == yaml.MappingNode && value.Tag != tagReplace {
+			for j := 1; j < len(value.Content); j += 2 {
+				if value.Content[j].Tag != tagRemove {
+					value.Content[j].Tag = tagReplace
+				}
+			}
+			continue
+		}
+		value.Tag = tagReplace
+	}
+}
+
+// fill adds the values of src that dst does not have: missing keys, and list
+// entries with a new value or code. Values dst has are kept as they are.
+func (m *merger) fill(dst, src *yaml.Node, layer *Layer) *yaml.Node {
+	if src.Tag == tagRemove {
+		return dst
+	}
+	if dst == nil {
+		return m.clone(src, layer)
+	}
+	if dst.Kind != src.Kind {
+		return dst
+	}
+
+	switch src.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+			if j := mappingIndex(dst, key.Value); j >= 0 {
+				dst.Content[j+1] = m.fill(dst.Content[j+1], value, layer)
+			} else if value.Tag != tagRemove {
+				dst.Content = append(dst.Content, m.clone(key, layer), m.clone(value, layer))
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range src.Content {
+			if sequenceIndex(dst, item) < 0 {
+				dst.Content = append(dst.Content, m.clone(item, layer))
+			}
+		}
+	}
+	return dst
+}
+
+// diffNodes appends the changes from a to b below path
+func diffNodes(changes []Change, path string, a, b *yaml.Node) []Change {
+	switch {
+	case a == nil && b.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(b.Content); i += 2 {
+			changes = diffNodes(changes, joinPath(path, b.Content[i].Value), nil, b.Content[i+1])
+		}
+		return changes
+	case a == nil && b.Kind == yaml.SequenceNode:
+		return append(changes, Change{Op: "+", Path: path, Value: listSummary(entries(b))})
+	case a == nil:
+		return append(changes, Change{Op: "+", Path: path, Value: summarize(b)})
+	case b == nil:
+		return append(changes, Change{Op: "-", Path: path})
+	case a.Kind != b.Kind || a.Kind == yaml.ScalarNode:
+		if from, to := summarize(a), summarize(b); from != to {
+			changes = append(changes, Change{Op: "~", Path: path, Value: from + " -> " + to})
+		}
+		return changes
+	}
+
+	if a.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			key := a.Content[i].Value
+			if key == "version" {
+				continue
+			}
+			var value *yaml.Node
+			if j := mappingIndex(b, key); j >= 0 {
+				value = b.Content[j+1]
+			}
+			changes = diffNodes(changes, joinPath(path, key), a.Content[i+1], value)
+		}
+		for i := 0; i+1 < len(b.Content); i += 2 {
+			if key := b.Content[i].Value; key != "version" && mappingIndex(a, key) < 0 {
+				changes = diffNodes(changes, joinPath(path, key), nil, b.Content[i+1])
+			}
+		}
+		return changes
+	}
+
+	// lists: entries are matched by value or code, like when layers merge
+	var added, removed []string
+	for _, item := range a.Content {
+		j := sequenceIndex(b, item)
+		switch {
+		case j < 0:
+			removed = append(removed, summarize(item))
+		case item.Kind == yaml.MappingNode:
+			changes = diffNodes(changes, joinPath(path, itemKey(item)), item, b.Content[j])
+		}
+	}
+	for _, item := range b.Content {
+		if sequenceIndex(a, item) < 0 {
+			added = append(added, summarize(item))
+		}
+	}
+	if len(added) > 0 {
+		changes = append(changes, Change{Op: "+", Path: path, Value: listSummary(added)})
+	}
+	if len(removed) > 0 {
+		changes = append(changes, Change{Op: "-", Path: path, Value: listSummary(removed)})
+	}
+	return changes
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// summarize describes a value in a few words: scalars by their value, lists
+// by their entries and frontends, backends and other entries with a code by
+// their code and description
+func summarize(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Value
+	case yaml.SequenceNode:
+		return "[" + listSummary(entries(n)) + "]"
+	case yaml.MappingNode:
+		code := itemKey(n)
+		if code == "" {
+			return fmt.Sprintf("{%d keys}", len(n.Content)/2)
+		}
+		if i := mappingIndex(n, "description"); i >= 0 && n.Content[i+1].Value != "" {
+			return fmt.Sprintf("%s (%s)", code, n.Content[i+1].Value)
+		}
+		return code
+	}
+	return ""
+}
+
+// entries summarizes every entry of a list
+func entries(list *yaml.Node) []string {
+	items := make([]string, len(list.Content))
+	for i, item := range list.Content {
+		items[i] = summarize(item)
+	}
+	return items
+}
+
+// listSummary joins entries, shortening long lists
+func listSummary(items []string) string {
+	const shown = 10
+	if len(items) <= shown {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:shown], ", "), len(items)-shown)
+}
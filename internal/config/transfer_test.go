@@ -0,0 +1,180 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestImport(t *testing.T) {
+	const base = `case: snake
+frontends:
+  - {code: rct, description: React}
+categories:
+  food: [pizza, taco]
+  space: [comet]
+`
+	const file = `case: kebab
+frontends:
+  - {code: rct, description: React 19}
+  - {code: vue, description: Vue}
+categories:
+  food: [sushi]
+`
+
+	tests := []struct {
+		name     string
+		file     string // name of the imported file, which sets its format
+		data     string
+		strategy string
+
+		wantCase      string
+		wantFrontends []string            // "code (description)"
+		wantWords     map[string][]string // nil words for a removed category
+		wantChanges   []string
+		wantErr       string
+	}{
+		{
+			name:          "merge",
+			file:          "import.yaml",
+			data:          file,
+			strategy:      ImportMerge,
+			wantCase:      "kebab",
+			wantFrontends: []string{"rct (React 19)", "vue (Vue)"},
+			wantWords:     map[string][]string{"food": {"pizza", "taco", "sushi"}, "space": {"comet"}},
+			wantChanges: []string{
+				"+ categories.food.words: sushi",
+				"~ frontends.rct.description: React -> React 19",
+				"+ frontends: vue (Vue)",
+				"~ case: snake -> kebab",
+			},
+		},
+		{
+			name:          "replace",
+			file:          "import.yaml",
+			data:          file,
+			strategy:      ImportReplace,
+			wantCase:      "kebab",
+			wantFrontends: []string{"rct (React 19)", "vue (Vue)"},
+			wantWords:     map[string][]string{"food": {"sushi"}, "space": {"comet"}},
+			wantChanges: []string{
+				"+ categories.food.words: sushi",
+				"- categories.food.words: pizza, taco",
+				"~ frontends.rct.description: React -> React 19",
+				"+ frontends: vue (Vue)",
+				"~ case: snake -> kebab",
+			},
+		},
+		{
+			name:          "skip existing",
+			file:          "import.yaml",
+			data:          file,
+			strategy:      ImportSkipExisting,
+			wantCase:      "snake",
+			wantFrontends: []string{"rct (React)", "vue (Vue)"},
+			wantWords:     map[string][]string{"food": {"pizza", "taco", "sushi"}, "space": {"comet"}},
+			wantChanges: []string{
+				"+ categories.food.words: sushi",
+				"+ frontends: vue (Vue)",
+			},
+		},
+		{
+			name:          "merge directives",
+			file:          "import.yaml",
+			data:          "categories:\n  food: !remove [taco]\n  space: !remove\n",
+			strategy:      ImportMerge,
+			wantCase:      "snake",
+			wantFrontends: []string{"rct (React)"},
+			wantWords:     map[string][]string{"food": {"pizza"}, "space": nil},
+			wantChanges: []string{
+				"- categories.food.words: taco",
+				"- categories.space",
+			},
+		},
+		{
+			name:          "TOML file",
+			file:          "import.toml",
+			data:          "case = \"kebab\"\n\n[[frontends]]\ncode = \"vue\"\ndescription = \"Vue\"\n",
+			strategy:      ImportMerge,
+			wantCase:      "kebab",
+			wantFrontends: []string{"rct (React)", "vue (Vue)"},
+			wantWords:     map[string][]string{"food": {"pizza", "taco"}},
+			wantChanges:   []string{"+ frontends: vue (Vue)", "~ case: snake -> kebab"},
+		},
+		{
+			name:     "file with errors",
+			file:     "import.yaml",
+			data:     "frontends:\n  - code: vue\n  - code: vue\n",
+			strategy: ImportMerge,
+			wantErr:  "import.yaml:3:11: error: duplicate frontend code 'vue' (first on line 2)",
+		},
+		{
+			name:     "unknown strategy",
+			file:     "import.yaml",
+			data:     file,
+			strategy: "overwrite",
+			wantErr:  "unknown import strategy 'overwrite'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Config
+			if err := yaml.Unmarshal([]byte(base), &c); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, changes, err := c.Import(path, tt.strategy)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Import() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+
+			if result.Case != tt.wantCase {
+				t.Errorf("case = %q, want %q", result.Case, tt.wantCase)
+			}
+			var frontends []string
+			for _, fe := range result.Frontends {
+				frontends = append(frontends, fe.Code+" ("+fe.Description+")")
+			}
+			if !reflect.DeepEqual(frontends, tt.wantFrontends) {
+				t.Errorf("frontends = %v, want %v", frontends, tt.wantFrontends)
+			}
+			for category, want := range tt.wantWords {
+				got, ok := result.Categories[category]
+				if want == nil && ok {
+					t.Errorf("category %s was not removed", category)
+					continue
+				}
+				if !reflect.DeepEqual(got.Words, want) {
+					t.Errorf("words of %s = %v, want %v", category, got.Words, want)
+				}
+			}
+
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.wantChanges) {
+				t.Errorf("changes =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.wantChanges, "\n"))
+			}
+
+			if c.Case != "snake" || len(c.Categories["food"].Words) != 2 {
+				t.Errorf("Import() changed the config it was called on")
+			}
+		})
+	}
+}
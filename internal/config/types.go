@@ -1,5 +1,12 @@
 package config
 
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
 // TechStack represents a technology stack configuration
 type TechStack struct {
 	Code        string `yaml:"code"`
@@ -24,13 +31,118 @@ type Backend struct {
 	Description string `yaml:"description"`
 }
 
+// Category represents a named word list. In YAML it is either a plain list of
+// words or a mapping with a sampling weight, optional per-word weights and a
+// part of speech used by composition patterns:
+//
+//	food: [pizza, taco]
+//	food: {weight: 3, words: [pizza, taco], word_weights: {pizza: 5}}
+//	moods: {pos: adjective, words: [goofy, quirky]}
+//	food: {words: [pizza, taco], emoji: {pizza: "🍕", taco: "🌮"}}
+//	food: {description: Food and drinks, order: 1, words: [pizza, taco]}
+//
+// Description, examples and order only affect how categories are listed.
+// Hidden categories are left out of listings and of "all", but can still be
+// picked by name and supply words to composition patterns.
+type Category struct {
+	Description string             `yaml:"description,omitempty"`
+	Examples    []string           `yaml:"examples,omitempty"` // shown instead of the first words
+	Hidden      bool               `yaml:"hidden,omitempty"`
+	Order       int                `yaml:"order,omitempty"` // lower first, unordered categories last
+	Pos         string             `yaml:"pos,omitempty"`
+	Weight      float64            `yaml:"weight,omitempty"`
+	Words       []string           `yaml:"words"`
+	WordWeights map[string]float64 `yaml:"word_weights,omitempty"`
+	Emoji       map[string]string  `yaml:"emoji,omitempty"` // word -> emoji shown with --emoji
+
+	wordFiles map[string]string // word -> word list file it was merged from
+	fileOnly  bool              // the category only exists in word list files
+}
+
+// categoryFields avoids recursing into Category's own YAML methods
+type categoryFields Category
+
+// UnmarshalYAML accepts both the plain list and the mapping form
+func (c *Category) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		*c = Category{}
+		return node.Decode(&c.Words)
+	}
+
+	var fields categoryFields
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+	switch fields.Pos {
+	case "", "adjective", "noun", "verb":
+	default:
+		return fmt.Errorf("line %d: unknown part of speech '%s' (use adjective, noun or verb)", node.Line, fields.Pos)
+	}
+	if fields.Order < 0 {
+		return fmt.Errorf("line %d: category order cannot be negative", node.Line)
+	}
+	if fields.Weight < 0 {
+		return fmt.Errorf("line %d: category weight cannot be negative", node.Line)
+	}
+	for word, weight := range fields.WordWeights {
+		if weight < 0 {
+			return fmt.Errorf("line %d: weight of word '%s' cannot be negative", node.Line, word)
+		}
+	}
+
+	*c = Category(fields)
+	return nil
+}
+
+// MarshalYAML writes categories without settings or metadata in the plain list form
+func (c Category) MarshalYAML() (interface{}, error) {
+	if c.Pos == "" && c.Weight == 0 && len(c.WordWeights) == 0 && len(c.Emoji) == 0 &&
+		c.Description == "" && len(c.Examples) == 0 && !c.Hidden && c.Order == 0 {
+		return c.Words, nil
+	}
+	return categoryFields(c), nil
+}
+
 // Config represents the user's custom configuration
 type Config struct {
+	Version int `yaml:"version,omitempty"` // schema version, see CurrentVersion
+
+	// TechStacks and Frameworks are the version 0 model of frontends and
+	// backends, kept so that stacks the migration could not place still load
 	TechStacks []TechStack            `yaml:"tech_stacks,omitempty"`
 	Frameworks map[string][]Framework `yaml:"frameworks,omitempty"`
-	Categories map[string][]string    `yaml:"categories,omitempty"`
+	Categories map[string]Category    `yaml:"categories,omitempty"`
 	Frontends  []Frontend             `yaml:"frontends,omitempty"`
 	Backends   []Backend              `yaml:"backends,omitempty"`
+	Layout     string                 `yaml:"layout,omitempty"`
+	Sampling   string                 `yaml:"sampling,omitempty"`
+	Compose    string                 `yaml:"compose,omitempty"`
+	Case       string                 `yaml:"case,omitempty"`
+	Separator  string                 `yaml:"separator,omitempty"`
+
+	MaxLength     int `yaml:"max_length,omitempty"`      // longest generated name, 0 means unlimited
+	MaxWordLength int `yaml:"max_word_length,omitempty"` // longer words are skipped
+	MaxCodeLength int `yaml:"max_code_length,omitempty"` // longer frontend and backend codes are abbreviated
+	SuffixDigits  int `yaml:"suffix_digits,omitempty"`   // trailing digits of pronounceable suffixes
+
+	Blocklist   Blocklist `yaml:"blocklist,omitempty"`
+	SafeForWork *bool     `yaml:"safe_for_work,omitempty"` // built-in filter, on unless set to false
+
+	Emojis         bool   `yaml:"emojis,omitempty"`          // prefix names with the emoji of their word
+	IncludeNumbers *bool  `yaml:"include_numbers,omitempty"` // digits in suffixes, on unless set to false
+	Portability    string `yaml:"portability,omitempty"`     // native, ascii or posix
+	Alliterate     string `yaml:"alliterate,omitempty"`      // any, frontend, backend or both
+}
+
+// Blocklist lists words and substrings that generated names must not contain
+type Blocklist struct {
+	Words      []string `yaml:"words,omitempty"`      // whole words, matched ignoring case
+	Substrings []string `yaml:"substrings,omitempty"` // matched anywhere in words and suffixes
+}
+
+// SafeForWorkEnabled reports whether the built-in safe for work filter applies
+func (c *Config) SafeForWorkEnabled() bool {
+	return c.SafeForWork == nil || *c.SafeForWork
 }
 
 // NewConfig creates a new empty config
@@ -38,8 +150,89 @@ func NewConfig() *Config {
 	return &Config{
 		TechStacks: []TechStack{},
 		Frameworks: make(map[string][]Framework),
-		Categories: make(map[string][]string),
+		Categories: make(map[string]Category),
 		Frontends:  []Frontend{},
 		Backends:   []Backend{},
 	}
 }
+
+// WordLists returns the words of every category
+func (c *Config) WordLists() map[string][]string {
+	lists := make(map[string][]string, len(c.Categories))
+	for name, cat := range c.Categories {
+		lists[name] = cat.Words
+	}
+	return lists
+}
+
+// CategoryNames returns the category names in display order: by their order,
+// then alphabetically. Hidden categories are left out unless includeHidden is set.
+func (c *Config) CategoryNames(includeHidden bool) []string {
+	names := make([]string, 0, len(c.Categories))
+	for name, cat := range c.Categories {
+		if includeHidden || !cat.Hidden {
+			names = append(names, name)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		a, b := c.Categories[names[i]].Order, c.Categories[names[j]].Order
+		switch {
+		case a == b:
+			return names[i] < names[j]
+		case a == 0 || b == 0:
+			return b == 0
+		}
+		return a < b
+	})
+	return names
+}
+
+// CategoryWeights returns the weights of categories that set one
+func (c *Config) CategoryWeights() map[string]float64 {
+	weights := make(map[string]float64)
+	for name, cat := range c.Categories {
+		if cat.Weight > 0 {
+			weights[name] = cat.Weight
+		}
+	}
+	return weights
+}
+
+// WordWeights returns the per-word weights of categories that set any
+func (c *Config) WordWeights() map[string]map[string]float64 {
+	weights := make(map[string]map[string]float64)
+	for name, cat := range c.Categories {
+		if len(cat.WordWeights) > 0 {
+			weights[name] = cat.WordWeights
+		}
+	}
+	return weights
+}
+
+// PartsOfSpeech returns the part of speech of categories that set one
+func (c *Config) PartsOfSpeech() map[string]string {
+	tags := make(map[string]string)
+	for name, cat := range c.Categories {
+		if cat.Pos != "" {
+			tags[name] = cat.Pos
+		}
+	}
+	return tags
+}
+
+// WordEmojis returns the emoji table of every category that has one
+func (c *Config) WordEmojis() map[string]map[string]string {
+	tables := make(map[string]map[string]string)
+	for name, cat := range c.Categories {
+		if len(cat.Emoji) > 0 {
+			tables[name] = cat.Emoji
+		}
+	}
+	return tables
+}
+
+// NumbersEnabled reports whether suffixes may contain digits
+func (c *Config) NumbersEnabled() bool {
+	return c.IncludeNumbers == nil || *c.IncludeNumbers
+}
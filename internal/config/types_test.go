@@ -0,0 +1,101 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCategoryNames(t *testing.T) {
+	cfg := &Config{Categories: map[string]Category{
+		"zoo":     {Words: []string{"otter"}},
+		"alpha":   {Words: []string{"comet"}},
+		"food":    {Order: 2, Words: []string{"pizza"}},
+		"dev":     {Order: 1, Words: []string{"commit"}},
+		"pop":     {Order: 2, Words: []string{"ninja"}},
+		"secret":  {Hidden: true, Words: []string{"hush"}},
+		"moods":   {Hidden: true, Order: 1, Pos: "adjective", Words: []string{"goofy"}},
+		"unnamed": {},
+	}}
+
+	want := []string{"dev", "food", "pop", "alpha", "unnamed", "zoo"}
+	if got := cfg.CategoryNames(false); !reflect.DeepEqual(got, want) {
+		t.Errorf("CategoryNames(false) = %v, want %v", got, want)
+	}
+
+	want = []string{"dev", "moods", "food", "pop", "alpha", "secret", "unnamed", "zoo"}
+	if got := cfg.CategoryNames(true); !reflect.DeepEqual(got, want) {
+		t.Errorf("CategoryNames(true) = %v, want %v", got, want)
+	}
+}
+
+func TestCategoryMetadataYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Category
+		wantErr string
+	}{
+		{
+			name:  "plain list",
+			input: "[pizza, taco]",
+			want:  Category{Words: []string{"pizza", "taco"}},
+		},
+		{
+			name:  "metadata",
+			input: "{description: Food and drinks, examples: [pizza], order: 1, hidden: true, words: [pizza, taco]}",
+			want: Category{
+				Description: "Food and drinks",
+				Examples:    []string{"pizza"},
+				Order:       1,
+				Hidden:      true,
+				Words:       []string{"pizza", "taco"},
+			},
+		},
+		{
+			name:    "negative order",
+			input:   "{order: -1, words: [pizza]}",
+			wantErr: "category order cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Category
+			err := yaml.Unmarshal([]byte(tt.input), &got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Unmarshal error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Unmarshal = %+v, want %+v", got, tt.want)
+			}
+
+			// Writing the category back keeps the metadata
+			data, err := yaml.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var again Category
+			if err := yaml.Unmarshal(data, &again); err != nil {
+				t.Fatalf("Unmarshal of %q: %v", data, err)
+			}
+			if !reflect.DeepEqual(again, tt.want) {
+				t.Errorf("round trip through %q = %+v, want %+v", data, again, tt.want)
+			}
+		})
+	}
+
+	// Categories without metadata stay in the plain list form
+	data, err := yaml.Marshal(Category{Words: []string{"pizza"}})
+	if err != nil || strings.TrimSpace(string(data)) != "- pizza" {
+		t.Errorf("Marshal = %q, %v, want a plain list", data, err)
+	}
+}
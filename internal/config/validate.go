@@ -0,0 +1,413 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aravindcm49/dir-init/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is an issue found by Validate
+type Problem struct {
+	File    string // config file, word list or environment variable
+	Line    int    // 0 when the position is unknown
+	Column  int
+	Warning bool // the config still works, but probably not as intended
+	Message string
+}
+
+// String formats the problem as "file:line:column: error: message"
+func (p Problem) String() string {
+	pos := p.File
+	switch {
+	case p.Line > 0 && p.Column > 0:
+		pos = fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+	case p.Line > 0:
+		pos = fmt.Sprintf("%s:%d", p.File, p.Line)
+	}
+	level := "error"
+	if p.Warning {
+		level = "warning"
+	}
+	return fmt.Sprintf("%s: %s: %s", pos, level, p.Message)
+}
+
+// HasErrors reports whether any of the problems is an error
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if !p.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the system, user and project config files and the word
+// lists of the store, then the config merged from them:
+//
+//   - YAML syntax and value types, and unknown keys
+//   - schema versions newer than CurrentVersion
+//   - empty, invalid or duplicate frontend, backend, tech stack and framework codes
+//   - invalid or duplicate words of a category, and categories without words
+//   - codes and words in several lists, and words containing "-"
+//
+// Every problem is reported, not just the first one.
+func (s *Store) Validate() []Problem {
+	if s.err != nil {
+		return []Problem{{File: "config", Message: s.err.Error()}}
+	}
+
+	// the files as they are on disk: older versions are checked without
+	// being upgraded, so every problem is reported where it is
+	paths := s.userPaths()
+	var files []string
+	for _, path := range []string{systemConfigPath, paths.Config, FindProjectConfig()} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+
+	var problems []Problem
+	for _, path := range files {
+		problems = append(problems, checkConfigFile(path)...)
+	}
+	if _, err := wordFileLayers(paths.Words); err != nil {
+		problems = append(problems, wordListProblems(err)...)
+	}
+	if _, err := envLayers(); err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			problems = append(problems, Problem{File: "environment", Message: line})
+		}
+	}
+
+	// what the files add up to, unless they cannot be merged
+	layered, err := s.LoadLayered()
+	if err != nil {
+		if HasErrors(problems) {
+			// the same errors, reported above by file
+			return append(problems, Problem{File: "merged config", Warning: true, Message: "not checked until the errors above are fixed"})
+		}
+		return append(problems, Problem{File: paths.Config, Message: err.Error()})
+	}
+	return append(problems, layered.check()...)
+}
+
+// fileChecker collects the problems of one config file
+type fileChecker struct {
+	path     string
+	problems []Problem
+}
+
+func (c *fileChecker) report(n *yaml.Node, warning bool, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{
+		File:    c.path,
+		Line:    n.Line,
+		Column:  n.Column,
+		Warning: warning,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkConfigFile checks a config file on its own
+func checkConfigFile(path string) []Problem {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []Problem{{File: path, Message: err.Error()}}
+	}
+
+	doc, err := parseDocument(data, FormatOf(path))
+	if err != nil {
+		return yamlProblems(path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	c := &fileChecker{path: path}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		c.report(root, false, "expected a mapping of settings")
+		return c.problems
+	}
+
+	if version, err := nodeVersion(root); err != nil {
+		c.problems = append(c.problems, yamlProblems(path, err)...)
+	} else if version > CurrentVersion {
+		c.report(root.Content[mappingIndex(root, "version")+1], false,
+			"config version %d is newer than this dir-init supports (%d)", version, CurrentVersion)
+		return c.problems
+	} else if migration, err := migrateNode(withoutDirectives(root), nil); err == nil {
+		switch {
+		case len(migration.Pending) > 0:
+			c.report(root, true, "config version %d cannot be upgraded until tech stacks %s are placed; run 'dir-init config migrate'",
+				version, strings.Join(migration.Pending, ", "))
+		case len(migration.Changes) > 1:
+			// more than setting the version, which files without one do not need
+			c.report(root, true, "config version %d is upgraded in memory until 'dir-init config migrate' rewrites it: %s", version, strings.Join(migration.Changes[:len(migration.Changes)-1], "; "))
+		}
+	}
+
+	c.checkRoot(root)
+
+	// value types, reported the way loading the config reports them
+	var config Config
+	if err := withoutDirectives(root).Decode(&config); err != nil {
+		c.problems = append(c.problems, yamlProblems(path, err)...)
+	}
+	return c.problems
+}
+
+func (c *fileChecker) checkRoot(root *yaml.Node) {
+	known := yamlKeys(reflect.TypeOf(Config{}))
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if !known[key.Value] {
+			c.report(key, false, "unknown key '%s'", key.Value)
+			continue
+		}
+		if value.Tag == tagRemove {
+			continue
+		}
+
+		switch key.Value {
+		case "frontends", "backends", "tech_stacks":
+			c.checkCodes(value, strings.TrimSuffix(strings.ReplaceAll(key.Value, "_", " "), "s"))
+		case "frameworks":
+			if value.Kind == yaml.MappingNode {
+				for j := 1; j < len(value.Content); j += 2 {
+					c.checkCodes(value.Content[j], "framework")
+				}
+			}
+		case "categories":
+			c.checkCategories(value)
+		case "blocklist":
+			c.checkKeys(value, reflect.TypeOf(Blocklist{}), "blocklist")
+		}
+	}
+}
+
+// checkKeys reports the keys of a mapping that the YAML tags of t do not name
+func (c *fileChecker) checkKeys(n *yaml.Node, t reflect.Type, what string) {
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+	known := yamlKeys(t)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if key := n.Content[i]; !known[key.Value] {
+			c.report(key, false, "unknown key '%s' in %s", key.Value, what)
+		}
+	}
+}
+
+// checkCodes checks a list of frontends, backends, tech stacks or frameworks
+func (c *fileChecker) checkCodes(list *yaml.Node, kind string) {
+	if list.Kind != yaml.SequenceNode {
+		return
+	}
+
+	seen := make(map[string]*yaml.Node)
+	for _, item := range list.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		c.checkKeys(item, reflect.TypeOf(Frontend{}), kind)
+
+		i := mappingIndex(item, "code")
+		if i < 0 || item.Content[i+1].Value == "" {
+			c.report(item, false, "%s without a code", kind)
+			continue
+		}
+
+		code := item.Content[i+1]
+		switch {
+		case strings.TrimSpace(code.Value) != code.Value || !utils.IsValidDirectoryName("a-"+code.Value+"-b"):
+			c.report(code, false, "%s code '%s' cannot be used in directory names", kind, code.Value)
+		case strings.Contains(code.Value, "-"):
+			c.report(code, true, "%s code '%s' contains '-', which also separates the parts of names", kind, code.Value)
+		}
+		if first, dup := seen[code.Value]; dup {
+			c.report(code, false, "duplicate %s code '%s' (first on line %d)", kind, code.Value, first.Line)
+			continue
+		}
+		seen[code.Value] = code
+	}
+}
+
+func (c *fileChecker) checkCategories(categories *yaml.Node) {
+	if categories.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(categories.Content); i += 2 {
+		name, cat := categories.Content[i], categories.Content[i+1]
+		if name.Value == "all" {
+			c.report(name, false, "category name 'all' is reserved for every category")
+		} else if ValidateWord(name.Value) != nil {
+			c.report(name, false, "invalid category name '%s' (use letters and digits, optionally joined by hyphens)", name.Value)
+		}
+		if cat.Tag == tagRemove {
+			continue
+		}
+
+		words := cat
+		if cat.Kind == yaml.MappingNode {
+			c.checkKeys(cat, reflect.TypeOf(categoryFields{}), fmt.Sprintf("category '%s'", name.Value))
+			j := mappingIndex(cat, "words")
+			if j < 0 || cat.Content[j+1].Tag == tagRemove {
+				continue
+			}
+			words = cat.Content[j+1]
+		}
+		c.checkWords(name.Value, words)
+	}
+}
+
+// checkWords checks the word list of a category
+func (c *fileChecker) checkWords(category string, list *yaml.Node) {
+	if list.Kind != yaml.SequenceNode {
+		return
+	}
+
+	seen := make(map[string]*yaml.Node)
+	for _, w := range list.Content {
+		if w.Kind != yaml.ScalarNode {
+			continue
+		}
+		if err := ValidateWord(w.Value); err != nil {
+			c.report(w, false, "category '%s': %v", category, err)
+			continue
+		}
+		if first, dup := seen[w.Value]; dup {
+			c.report(w, false, "duplicate word '%s' in category '%s' (first on line %d)", w.Value, category, first.Line)
+			continue
+		}
+		seen[w.Value] = w
+
+		if strings.Contains(w.Value, "-") {
+			c.report(w, true, "word '%s' contains '-', which also separates the parts of names; 'dir-init parse' recovers it only while it is in the config", w.Value)
+		}
+	}
+}
+
+// check looks for problems that only show once the layers are merged
+func (l *LayeredConfig) check() []Problem {
+	var problems []Problem
+	report := func(n *yaml.Node, warning bool, format string, args ...interface{}) {
+		p := Problem{Warning: warning, Message: fmt.Sprintf(format, args...)}
+		if origin := l.origins[n]; origin != nil {
+			p.File = origin.Source
+			if origin.Name != LayerEnv && origin.Name != LayerFlags && n.Line > 0 {
+				p.Line, p.Column = n.Line, n.Column
+			}
+		}
+		problems = append(problems, p)
+	}
+	if l.root == nil {
+		return nil
+	}
+
+	// categories without words, and words in several categories
+	if i := mappingIndex(l.root, "categories"); i >= 0 {
+		categories := l.root.Content[i+1]
+		firstCategory := make(map[string]string)
+		for j := 0; j+1 < len(categories.Content); j += 2 {
+			name, cat := categories.Content[j], categories.Content[j+1]
+			k := mappingIndex(cat, "words")
+			if k < 0 || len(cat.Content[k+1].Content) == 0 {
+				report(name, false, "category '%s' has no words", name.Value)
+				continue
+			}
+			for _, w := range cat.Content[k+1].Content {
+				if first, ok := firstCategory[w.Value]; ok {
+					report(w, true, "word '%s' is in categories '%s' and '%s'", w.Value, first, name.Value)
+					continue
+				}
+				firstCategory[w.Value] = name.Value
+			}
+		}
+	}
+
+	// codes that are both a frontend and a backend, except the "none" placeholder
+	if i, j := mappingIndex(l.root, "frontends"), mappingIndex(l.root, "backends"); i >= 0 && j >= 0 {
+		frontends := make(map[string]bool)
+		for _, fe := range l.root.Content[i+1].Content {
+			frontends[itemKey(fe)] = true
+		}
+		for _, be := range l.root.Content[j+1].Content {
+			if code := itemKey(be); code != "none" && frontends[code] {
+				k := mappingIndex(be, "code")
+				report(be.Content[k+1], true, "code '%s' is both a frontend and a backend", code)
+			}
+		}
+	}
+	return problems
+}
+
+// yamlKeys returns the YAML keys of the exported fields of a struct type
+func yamlKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if key, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); key != "" && key != "-" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// yamlLinePattern finds the line in the errors of the YAML package
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlProblems turns a YAML syntax or type error into problems
+func yamlProblems(path string, err error) []Problem {
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+
+	problems := make([]Problem, 0, len(messages))
+	for _, msg := range messages {
+		p := Problem{File: path, Message: msg}
+		if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+			p.Line, _ = strconv.Atoi(m[1])
+			p.Message = m[2]
+		}
+		problems = append(problems, p)
+	}
+	return problems
+}
+
+// wordListLinePattern splits the "file:line: message" errors of ReadWordList
+var wordListLinePattern = regexp.MustCompile(`^(.*):(\d+): (.*)$`)
+
+// wordListProblems turns the joined errors of word list files into problems
+func wordListProblems(err error) []Problem {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	problems := make([]Problem, 0, len(errs))
+	for _, e := range errs {
+		p := Problem{Message: e.Error()}
+		if m := wordListLinePattern.FindStringSubmatch(e.Error()); m != nil {
+			p.File, p.Message = m[1], m[3]
+			p.Line, _ = strconv.Atoi(m[2])
+		}
+		problems = append(problems, p)
+	}
+	return problems
+}
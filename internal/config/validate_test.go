@@ -0,0 +1,236 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckConfigFile(t *testing.T) {
+	tests := []struct {
+		name string
+		file string // name of the file, which sets its format
+		data string
+		want []string
+	}{
+		{
+			name: "valid file",
+			file: "config.yaml",
+			data: "version: 1\ncase: snake\nfrontends:\n  - code: rct\n",
+		},
+		{
+			name: "unknown keys",
+			file: "config.yaml",
+			data: "case: snake\ncolour: red\nblocklist:\n  word: [heck]\n",
+			want: []string{
+				"config.yaml:2:1: error: unknown key 'colour'",
+				"config.yaml:4:3: error: unknown key 'word' in blocklist",
+			},
+		},
+		{
+			name: "codes",
+			file: "config.yaml",
+			data: "frontends:\n  - code: rct\n  - code: vue\n  - code: rct\n  - description: Nothing\nbackends:\n  - code: my-api\n  - code: a/b\n",
+			want: []string{
+				"config.yaml:4:11: error: duplicate frontend code 'rct' (first on line 2)",
+				"config.yaml:5:5: error: frontend without a code",
+				"config.yaml:7:11: warning: backend code 'my-api' contains '-', which also separates the parts of names",
+				"config.yaml:8:11: error: backend code 'a/b' cannot be used in directory names",
+			},
+		},
+		{
+			name: "words",
+			file: "config.yaml",
+			data: "categories:\n  food: [pizza, taco, pizza, hot-dog]\n  all: [tofu]\n",
+			want: []string{
+				"config.yaml:2:23: error: duplicate word 'pizza' in category 'food' (first on line 2)",
+				"config.yaml:2:30: warning: word 'hot-dog' contains '-', which also separates the parts of names; 'dir-init parse' recovers it only while it is in the config",
+				"config.yaml:3:3: error: category name 'all' is reserved for every category",
+			},
+		},
+		{
+			name: "value types",
+			file: "config.yaml",
+			data: "case: snake\nmax_length: long\n",
+			want: []string{"config.yaml:2: error: cannot unmarshal !!str `long` into int"},
+		},
+		{
+			name: "newer version",
+			file: "config.yaml",
+			data: "version: 2\ncolour: red\n",
+			want: []string{"config.yaml:1:10: error: config version 2 is newer than this dir-init supports (1)"},
+		},
+		{
+			name: "older version",
+			file: "config.yaml",
+			data: "tech_stacks:\n  - code: bego\n",
+			want: []string{"config.yaml:1:1: warning: config version 0 is upgraded in memory until 'dir-init config migrate' rewrites it: moved tech stack 'bego' to backends as 'go'"},
+		},
+		{
+			name: "older version with pending stacks",
+			file: "config.yaml",
+			data: "tech_stacks:\n  - code: mobile\n",
+			want: []string{"config.yaml:1:1: warning: config version 0 cannot be upgraded until tech stacks mobile are placed; run 'dir-init config migrate'"},
+		},
+		{
+			name: "JSON positions",
+			file: "config.json",
+			data: "{\n  \"case\": \"snake\",\n  \"colour\": \"red\",\n  \"frontends\": [{\"code\": \"rct\"}, {\"code\": \"rct\"}]\n}\n",
+			want: []string{
+				"config.json:3:3: error: unknown key 'colour'",
+				"config.json:4:43: error: duplicate frontend code 'rct' (first on line 4)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, p := range checkConfigFile(path) {
+				got = append(got, strings.TrimPrefix(p.String(), dir+string(filepath.Separator)))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkConfigFile() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		files layerFiles
+		env   map[string]string
+		want  []string // problems, with paths relative to the store directory
+	}{
+		{
+			name: "problems of the merged layers",
+			files: layerFiles{
+				system: "categories:\n  food: [pizza]\n",
+				user:   "categories:\n  snacks: [pizza]\n  empty: []\nfrontends:\n  - code: js\nbackends:\n  - code: js\n  - code: none\n",
+			},
+			want: []string{
+				"user/config.yaml:2:12: warning: word 'pizza' is in categories 'food' and 'snacks'",
+				"user/config.yaml:3:3: error: category 'empty' has no words",
+				"user/config.yaml:7:11: warning: code 'js' is both a frontend and a backend",
+			},
+		},
+		{
+			name: "merged checks run next to file errors",
+			files: layerFiles{
+				user:    "colour: red\ncategories:\n  food: [pizza]\n",
+				project: "categories:\n  snacks: [pizza]\n",
+			},
+			want: []string{
+				"user/config.yaml:1:1: error: unknown key 'colour'",
+				"project/.dir-init.yaml:2:12: warning: word 'pizza' is in categories 'food' and 'snacks'",
+			},
+		},
+		{
+			name:  "files that cannot be merged",
+			files: layerFiles{user: "max_length: long\n"},
+			want: []string{
+				"user/config.yaml:1: error: cannot unmarshal !!str `long` into int",
+				"merged config: warning: not checked until the errors above are fixed",
+			},
+		},
+		{
+			name: "environment values",
+			env:  map[string]string{"DIR_INIT_MAX_LENGTH": "long"},
+			want: []string{
+				"environment: error: DIR_INIT_MAX_LENGTH: must be an integer",
+				"merged config: warning: not checked until the errors above are fixed",
+			},
+		},
+		{
+			name:  "word list files",
+			files: layerFiles{user: "case: snake\n", words: map[string]string{"food.txt": "pizza\nhot dog\n"}},
+			want: []string{
+				"user/words/food.txt:2: error: invalid word \"hot dog\" (use letters and digits, optionally joined by hyphens)",
+				"merged config: warning: not checked until the errors above are fixed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testStore(t, tt.files)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			dir := filepath.Dir(filepath.Dir(s.UserConfigPath()))
+			before := listFiles(t, dir)
+
+			var got []string
+			for _, p := range s.Validate() {
+				got = append(got, strings.TrimPrefix(p.String(), dir+string(filepath.Separator)))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+
+			if after := listFiles(t, dir); !reflect.DeepEqual(after, before) {
+				t.Errorf("Validate() changed the files: %v, before %v", after, before)
+			}
+		})
+	}
+}
+
+func TestValidateDefaults(t *testing.T) {
+	// the plain word lists that config init wrote before the config had a version
+	legacy := struct {
+		Frontends  []Frontend          `yaml:"frontends"`
+		Backends   []Backend           `yaml:"backends"`
+		Categories map[string][]string `yaml:"categories"`
+	}{DefaultConfig().Frontends, DefaultConfig().Backends, make(map[string][]string)}
+	for name, cat := range DefaultConfig().Categories {
+		if !cat.Hidden {
+			legacy.Categories[name] = cat.Words
+		}
+	}
+	data, err := yaml.Marshal(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		init func(s *Store) error
+	}{
+		{"built-in defaults", func(*Store) error { return nil }},
+		{"config init", func(s *Store) error { return s.InitConfig() }},
+		{"config init of older versions", func(s *Store) error {
+			return os.WriteFile(s.UserConfigPath(), data, 0o644)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testStore(t, layerFiles{})
+			if err := os.MkdirAll(filepath.Dir(s.UserConfigPath()), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.init(s); err != nil {
+				t.Fatal(err)
+			}
+
+			if problems := s.Validate(); HasErrors(problems) {
+				var lines []string
+				for _, p := range problems {
+					lines = append(lines, p.String())
+				}
+				t.Errorf("Validate() reports errors:\n%s", strings.Join(lines, "\n"))
+			}
+		})
+	}
+}
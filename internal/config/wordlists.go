@@ -0,0 +1,174 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// wordPattern matches letters and digits, optionally joined by hyphens
+var wordPattern = regexp.MustCompile(`^[\p{L}\p{N}]+(-[\p{L}\p{N}]+)*$`)
+
+// ValidateWord checks that a word can be used in directory names: letters and
+// digits, optionally joined by hyphens such as "cherry-pick"
+func ValidateWord(word string) error {
+	if !wordPattern.MatchString(word) {
+		return fmt.Errorf("invalid word %q (use letters and digits, optionally joined by hyphens)", word)
+	}
+	return nil
+}
+
+// ReadWordList reads one word per line. Blank lines and everything after a
+// "#" are ignored, and repeated words are kept once. Invalid entries are
+// reported as "name:line: ..." errors, all of them joined into one error.
+func ReadWordList(r io.Reader, name string) ([]string, error) {
+	var words []string
+	var errs []error
+	seen := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry, _, _ := strings.Cut(scanner.Text(), "#")
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if err := ValidateWord(entry); err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", name, line, err))
+			continue
+		}
+		if _, dup := seen[entry]; !dup {
+			seen[entry] = struct{}{}
+			words = append(words, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+
+	return words, errors.Join(errs...)
+}
+
+// ReadWordFile reads a word list file, or standard input for "-"
+func ReadWordFile(path string) ([]string, error) {
+	if path == "-" {
+		return ReadWordList(os.Stdin, "stdin")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadWordList(f, path)
+}
+
+// loadWordFiles merges the word lists of dir into the categories of the
+// config. A file named food.txt adds its words to the food category, creating
+// the category when the config does not have it.
+func (c *Config) loadWordFiles(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		words, err := ReadWordFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		c.mergeWords(strings.TrimSuffix(filepath.Base(path), ".txt"), words, path)
+	}
+	return errors.Join(errs...)
+}
+
+// mergeWords adds words to a category, remembering the file they came from
+// so that SaveConfig leaves them out of the config file
+func (c *Config) mergeWords(category string, words []string, path string) {
+	cat, exists := c.Categories[category]
+	if !exists {
+		cat.fileOnly = true
+	}
+	if cat.wordFiles == nil {
+		cat.wordFiles = make(map[string]string)
+	}
+
+	present := make(map[string]struct{}, len(cat.Words))
+	for _, w := range cat.Words {
+		present[w] = struct{}{}
+	}
+	for _, w := range words {
+		if _, ok := present[w]; !ok {
+			cat.Words = append(cat.Words, w)
+			cat.wordFiles[w] = path
+		}
+	}
+	c.Categories[category] = cat
+}
+
+// WordFile returns the word list file a word was merged from, or "" when the
+// word is part of the config file
+func (c Category) WordFile(word string) string {
+	return c.wordFiles[word]
+}
+
+// WithWords returns a copy of the config whose category also contains words,
+// creating the category when needed. The config itself is not changed.
+func (c *Config) WithWords(category string, words []string) *Config {
+	clone := *c
+	clone.Categories = make(map[string]Category, len(c.Categories)+1)
+	for name, cat := range c.Categories {
+		clone.Categories[name] = cat
+	}
+
+	cat := clone.Categories[category]
+	cat.Words = append([]string(nil), cat.Words...)
+	for _, w := range words {
+		if !containsWord(cat.Words, w) {
+			cat.Words = append(cat.Words, w)
+		}
+	}
+	clone.Categories[category] = cat
+	return &clone
+}
+
+// withoutWordFiles returns a copy of the config without the words merged from
+// word list files, which is what SaveConfig writes
+func (c *Config) withoutWordFiles() *Config {
+	clone := *c
+	clone.Categories = make(map[string]Category, len(c.Categories))
+	for name, cat := range c.Categories {
+		if len(cat.wordFiles) > 0 {
+			words := make([]string, 0, len(cat.Words))
+			for _, w := range cat.Words {
+				if _, fromFile := cat.wordFiles[w]; !fromFile {
+					words = append(words, w)
+				}
+			}
+			if cat.fileOnly && len(words) == 0 {
+				continue
+			}
+			cat.Words = words
+		}
+		clone.Categories[name] = cat
+	}
+	return &clone
+}
+
+func containsWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
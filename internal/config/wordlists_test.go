@@ -0,0 +1,77 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadWordList(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "words",
+			input: "pizza\ntaco\n",
+			want:  []string{"pizza", "taco"},
+		},
+		{
+			name:  "comments, blank lines and repeats",
+			input: "# snacks\n\npizza  # the best\n  taco\npizza\ncherry-pick\n",
+			want:  []string{"pizza", "taco", "cherry-pick"},
+		},
+		{
+			name:  "letters of any script",
+			input: "café\nsüßigkeit\n",
+			want:  []string{"café", "süßigkeit"},
+		},
+		{
+			name:    "invalid entries",
+			input:   "pizza\nhot dog\ntaco\n-x\n",
+			want:    []string{"pizza", "taco"},
+			wantErr: "food.txt:2: invalid word \"hot dog\" (use letters and digits, optionally joined by hyphens)\nfood.txt:4: invalid word \"-x\" (use letters and digits, optionally joined by hyphens)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := ReadWordList(strings.NewReader(tt.input), "food.txt")
+			if !reflect.DeepEqual(words, tt.want) {
+				t.Errorf("ReadWordList() = %v, want %v", words, tt.want)
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("ReadWordList: %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("ReadWordList() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithWords(t *testing.T) {
+	c := NewConfig()
+	c.Categories["food"] = Category{Description: "Food", Words: []string{"pizza"}}
+
+	tests := []struct {
+		category string
+		words    []string
+		want     []string
+	}{
+		{"food", []string{"taco", "pizza"}, []string{"pizza", "taco"}},
+		{"space", []string{"comet"}, []string{"comet"}},
+	}
+
+	for _, tt := range tests {
+		got := c.WithWords(tt.category, tt.words)
+		if words := got.Categories[tt.category].Words; !reflect.DeepEqual(words, tt.want) {
+			t.Errorf("WithWords(%s) words = %v, want %v", tt.category, words, tt.want)
+		}
+	}
+	if words := c.Categories["food"].Words; !reflect.DeepEqual(words, []string{"pizza"}) {
+		t.Errorf("WithWords() changed the config it was called on: %v", words)
+	}
+}
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Alliteration restricts words to the initials of the frontend and backend codes
+type Alliteration string
+
+const (
+	AlliterateOff      Alliteration = ""         // pick words freely
+	AlliterateAny      Alliteration = "any"      // the initial of the frontend or the backend
+	AlliterateFrontend Alliteration = "frontend" // the initial of the frontend
+	AlliterateBackend  Alliteration = "backend"  // the initial of the backend
+	AlliterateBoth     Alliteration = "both"     // the initial shared by frontend and backend
+)
+
+// ParseAlliteration converts a user supplied mode into an Alliteration
+func ParseAlliteration(s string) (Alliteration, error) {
+	switch a := Alliteration(strings.ToLower(strings.TrimSpace(s))); a {
+	case "", "off", "none":
+		return AlliterateOff, nil
+	case AlliterateAny, AlliterateFrontend, AlliterateBackend, AlliterateBoth:
+		return a, nil
+	}
+	return "", fmt.Errorf("unknown alliteration mode %q (use any, frontend, backend or both)", s)
+}
+
+// initial returns the lowercased first letter of a code, or 0 when it has none
+func initial(code string) rune {
+	for _, r := range code {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+	}
+	return 0
+}
+
+// alliterationInitials returns the initials words must start with for a
+// selection, falling back from both codes to either code, and then to no
+// restriction, when the categories have no word with those initials
+func (g *Generator) alliterationInitials(sel Selection) string {
+	if g.config.Alliteration == AlliterateOff || sel.Word != "" {
+		return ""
+	}
+
+	frontend, backend := initial(sel.Frontend), initial(sel.Backend)
+
+	var choices []string
+	add := func(letters ...rune) {
+		s := ""
+		for _, r := range letters {
+			if r != 0 && !strings.ContainsRune(s, r) {
+				s += string(r)
+			}
+		}
+		if s != "" {
+			choices = append(choices, s)
+		}
+	}
+
+	switch g.config.Alliteration {
+	case AlliterateFrontend:
+		add(frontend)
+	case AlliterateBackend:
+		add(backend)
+	case AlliterateBoth:
+		if frontend == backend {
+			add(frontend)
+		}
+		add(frontend, backend)
+	default:
+		add(frontend, backend)
+	}
+
+	for _, letters := range choices {
+		g.initials = letters
+		ok := g.hasWords(sel.Category)
+		g.initials = ""
+		if ok {
+			return letters
+		}
+	}
+	return ""
+}
+
+// hasWords reports whether every word the category or composition needs can be picked
+func (g *Generator) hasWords(category string) bool {
+	if g.config.Composition == nil {
+		return g.anyWords(g.candidateCategories(category))
+	}
+
+	for _, role := range g.config.Composition.roles {
+		if !g.anyWords(g.roleCategories(category, role)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Generator) anyWords(categories []string) bool {
+	for _, cat := range categories {
+		if len(g.words(cat)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// alliterates reports whether a word starts with one of the current initials
+func (g *Generator) alliterates(word string) bool {
+	return g.initials == "" || strings.ContainsRune(g.initials, initial(word))
+}
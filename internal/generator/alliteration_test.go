@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAlliteration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Alliteration
+		wantErr bool
+	}{
+		{"", AlliterateOff, false},
+		{"off", AlliterateOff, false},
+		{"None", AlliterateOff, false},
+		{" both ", AlliterateBoth, false},
+		{"frontend", AlliterateFrontend, false},
+		{"all", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAlliteration(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAlliteration(%q) = %q, %v, want %q (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestAlliterationInitials(t *testing.T) {
+	categories := map[string][]string{
+		"food":  {"ramen", "nachos", "pizza"},
+		"space": {"nova", "comet"},
+	}
+
+	tests := []struct {
+		name string
+		mode Alliteration
+		sel  Selection
+		want string
+	}{
+		{"off", AlliterateOff, Selection{Frontend: "rct", Backend: "node"}, ""},
+		{"any", AlliterateAny, Selection{Frontend: "rct", Backend: "node"}, "rn"},
+		{"frontend", AlliterateFrontend, Selection{Frontend: "rct", Backend: "node"}, "r"},
+		{"backend", AlliterateBackend, Selection{Frontend: "rct", Backend: "node"}, "n"},
+		{"both with a shared initial", AlliterateBoth, Selection{Frontend: "next", Backend: "node"}, "n"},
+		{"both without a shared initial", AlliterateBoth, Selection{Frontend: "rct", Backend: "node"}, "rn"},
+		{"codes starting with digits", AlliterateFrontend, Selection{Frontend: "11pwa"}, "p"},
+		{"no word with the initial", AlliterateFrontend, Selection{Frontend: "vue"}, ""},
+		{"the category has no word with the initial", AlliterateBackend, Selection{Backend: "rails", Category: "space"}, ""},
+		{"a custom word", AlliterateAny, Selection{Frontend: "rct", Word: "pizza"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(Config{Categories: categories, Alliteration: tt.mode})
+			if got := g.alliterationInitials(tt.sel); got != tt.want {
+				t.Errorf("alliterationInitials() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateAlliterates(t *testing.T) {
+	g := NewGenerator(Config{
+		Seed: 6,
+		Categories: map[string][]string{
+			"food":  {"ramen", "nachos", "pizza", "taco"},
+			"space": {"nova", "comet", "rocket"},
+		},
+		Alliteration:  AlliterateAny,
+		SkipDiskCheck: true,
+	})
+	layout := MustParseLayout("{frontend}-{backend}-{word}")
+
+	for i := 0; i < 4; i++ { // ramen, nachos, nova and rocket
+		name, err := g.GenerateName(layout, Selection{Frontend: "rct", Backend: "node"}, SuffixMixed, 4)
+		if err != nil {
+			t.Fatalf("GenerateName: %v", err)
+		}
+		word := strings.TrimPrefix(name, "rct-node-")
+		if !strings.HasPrefix(word, "r") && !strings.HasPrefix(word, "n") {
+			t.Errorf("GenerateName() = %q, want a word starting with r or n", name)
+		}
+	}
+
+	// The initials only apply while a name is generated
+	if g.initials != "" {
+		t.Errorf("initials = %q after GenerateName, want none", g.initials)
+	}
+}
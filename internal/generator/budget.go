@@ -0,0 +1,267 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// minCodeLength is the shortest a frontend or backend code is abbreviated to
+const minCodeLength = 2
+
+// ErrLengthBudget is returned when no name can be built within MaxLength
+var ErrLengthBudget = errors.New("no name fits the length budget")
+
+// LengthBudgetError explains why no name fits in MaxLength characters
+type LengthBudgetError struct {
+	MaxLength int
+	Category  string
+	Shortest  int // length of the shortest name the layout could produce
+}
+
+func (e *LengthBudgetError) Error() string {
+	return fmt.Sprintf("no name fits in %d characters: the shortest name for category '%s' needs %d",
+		e.MaxLength, e.Category, e.Shortest)
+}
+
+// Is lets errors.Is match LengthBudgetError against ErrLengthBudget
+func (e *LengthBudgetError) Is(target error) bool {
+	return target == ErrLengthBudget
+}
+
+// budget is the plan for rendering one name within the configured limits
+type budget struct {
+	frontend  string
+	backend   string
+	wordLimit int      // longest word that still fits, 0 means unlimited
+	suffixes  [][2]int // preferred and minimum length of every {suffix} token
+}
+
+// Abbreviate shortens a code to at most n characters by keeping its first
+// letter, dropping vowels from the rest and then truncating, so "express"
+// becomes "exprss" and, at 3 characters, "exp". n <= 0 leaves code unchanged.
+func Abbreviate(code string, n int) string {
+	if n <= 0 || len(code) <= n {
+		return code
+	}
+
+	b := []byte(code[:1])
+	rest := code[1:]
+	for i := len(rest) - 1; i >= 0 && len(b)+len(rest) > n; i-- {
+		if strings.IndexByte("aeiouAEIOU", rest[i]) >= 0 {
+			rest = rest[:i] + rest[i+1:]
+		}
+	}
+	b = append(b, rest...)
+
+	return string(b[:min(n, len(b))])
+}
+
+// planBudget decides how the frontend, backend, word and suffixes share
+// MaxLength. Words that are too long are skipped first; if no word fits, the
+// frontend and backend codes are abbreviated, and after that the suffixes
+// shrink towards their minimum length. If nothing fits, a LengthBudgetError
+// is returned.
+func (g *Generator) planBudget(layout *Layout, sel Selection, suffixType SuffixType, length int, now time.Time) (budget, error) {
+	b := budget{
+		frontend: Abbreviate(sel.Frontend, g.config.MaxCodeLength),
+		backend:  Abbreviate(sel.Backend, g.config.MaxCodeLength),
+	}
+
+	var fixed, wordTokens, codeTokens, suffixWant, suffixMin int
+	for _, t := range layout.tokens {
+		switch t.Kind {
+		case TokenLiteral:
+			fixed += len(t.Text)
+		case TokenDate:
+			fixed += len(now.Format(dateFormat(t)))
+		case TokenCategory:
+			fixed += g.longestCategory(sel.Category)
+		case TokenWord:
+			wordTokens++
+		case TokenFrontend, TokenBackend:
+			codeTokens++
+		case TokenSuffix:
+			st, sl := suffixTokenConfig(t, suffixType, length)
+			want, least := suffixLengths(st, sl)
+			b.suffixes = append(b.suffixes, [2]int{want, least})
+			suffixWant += want
+			suffixMin += least
+		}
+	}
+
+	if g.config.MaxLength <= 0 {
+		return b, nil
+	}
+
+	shortest := 0
+	if wordTokens > 0 {
+		shortest = g.shortestWord(sel)
+	}
+
+	codes := func() int {
+		n := 0
+		for _, t := range layout.tokens {
+			switch t.Kind {
+			case TokenFrontend:
+				n += len(b.frontend)
+			case TokenBackend:
+				n += len(b.backend)
+			}
+		}
+		return n
+	}
+
+	// fits sets the word limit for the given suffix cost and reports whether the shortest word fits
+	fits := func(suffixCost int) bool {
+		room := g.config.MaxLength - fixed - codes() - suffixCost
+		if wordTokens == 0 {
+			return room >= 0
+		}
+		b.wordLimit = room / wordTokens
+		return b.wordLimit >= shortest && b.wordLimit > 0
+	}
+
+	if fits(suffixWant) {
+		return b, nil
+	}
+
+	// Abbreviate the longer code one character at a time until the name fits
+	for codeTokens > 0 {
+		switch {
+		case len(b.frontend) >= len(b.backend) && len(b.frontend) > minCodeLength:
+			b.frontend = Abbreviate(b.frontend, len(b.frontend)-1)
+		case len(b.backend) > minCodeLength:
+			b.backend = Abbreviate(b.backend, len(b.backend)-1)
+		default:
+			codeTokens = 0
+			continue
+		}
+		if fits(suffixWant) {
+			return b, nil
+		}
+	}
+
+	// Then shorten the suffixes
+	if fits(suffixMin) {
+		return b, nil
+	}
+
+	category := sel.Category
+	if category == "" {
+		category = "all"
+	}
+	return b, &LengthBudgetError{
+		MaxLength: g.config.MaxLength,
+		Category:  category,
+		Shortest:  fixed + codes() + suffixMin + wordTokens*shortest,
+	}
+}
+
+// fitSuffixes returns the length of every suffix token once the rest of the
+// name takes used characters: each suffix starts at its minimum and grows
+// towards its preferred length, in layout order, while the budget allows
+func (g *Generator) fitSuffixes(b budget, used int) []int {
+	lengths := make([]int, len(b.suffixes))
+	room := g.config.MaxLength - used
+	for i, s := range b.suffixes {
+		lengths[i] = s[1]
+		room -= s[1]
+	}
+	for i, s := range b.suffixes {
+		if g.config.MaxLength <= 0 {
+			lengths[i] = s[0]
+			continue
+		}
+		grow := min(s[0]-s[1], max(room, 0))
+		lengths[i] += grow
+		room -= grow
+	}
+	return lengths
+}
+
+// suffixLengths returns the rendered length of a suffix, including its
+// leading "-", at the preferred length and at the shortest allowed length
+func suffixLengths(suffixType SuffixType, length int) (int, int) {
+	spec, ok := LookupSuffixSpec(suffixType)
+	if !ok {
+		spec, _ = LookupSuffixSpec(SuffixMixed)
+	}
+	return 1 + clampSuffixLength(suffixType, length), 1 + spec.MinLength
+}
+
+// shortestWord returns the length of the shortest word a selection can render
+func (g *Generator) shortestWord(sel Selection) int {
+	if sel.Word != "" {
+		return len(sel.Word)
+	}
+
+	if g.config.Composition != nil {
+		total := len(g.config.Composition.roles) - 1
+		for _, role := range g.config.Composition.roles {
+			total += g.shortestIn(g.roleCategories(sel.Category, role))
+		}
+		return total
+	}
+
+	return g.shortestIn(g.candidateCategories(sel.Category))
+}
+
+// shortestIn returns the length of the shortest acceptable word in the categories
+func (g *Generator) shortestIn(categories []string) int {
+	shortest := -1
+	for _, cat := range categories {
+		for _, w := range g.words(cat) {
+			if shortest < 0 || len(w) < shortest {
+				shortest = len(w)
+			}
+		}
+	}
+	if shortest < 0 {
+		return len("folder")
+	}
+	return shortest
+}
+
+// longestCategory returns the length of the longest category name a {category} token can render
+func (g *Generator) longestCategory(category string) int {
+	longest := 0
+	for _, cat := range g.candidateCategories(category) {
+		longest = max(longest, len(cat))
+	}
+	return longest
+}
+
+// acceptWord reports whether a word may be used under the current limits
+func (g *Generator) acceptWord(word string) bool {
+	if g.config.MaxWordLength > 0 && len(word) > g.config.MaxWordLength {
+		return false
+	}
+	if g.wordLimit > 0 && len(word) > g.wordLimit {
+		return false
+	}
+	if g.skipBlocked && g.config.Blocklist.BlocksWord(word) {
+		return false
+	}
+	if g.config.WordMatch != nil && !g.config.WordMatch.MatchString(word) {
+		return false
+	}
+	return g.alliterates(word)
+}
+
+// words returns the words of a category that pass acceptWord
+func (g *Generator) words(category string) []string {
+	words := g.config.Categories[category]
+	if g.config.MaxWordLength <= 0 && g.wordLimit <= 0 && !g.skipBlocked && g.initials == "" && g.config.WordMatch == nil {
+		return words
+	}
+
+	accepted := make([]string, 0, len(words))
+	for _, w := range words {
+		if g.acceptWord(w) {
+			accepted = append(accepted, w)
+		}
+	}
+	return accepted
+}
@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAbbreviate(t *testing.T) {
+	tests := []struct {
+		code string
+		n    int
+		want string
+	}{
+		{"express", 0, "express"},
+		{"express", 7, "express"},
+		{"express", 6, "exprss"},
+		{"express", 3, "exp"},
+		{"react", 4, "rect"},
+		{"react", 3, "rct"},
+		{"ab", 1, "a"},
+	}
+
+	for _, tt := range tests {
+		if got := Abbreviate(tt.code, tt.n); got != tt.want {
+			t.Errorf("Abbreviate(%q, %d) = %q, want %q", tt.code, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestLengthBudget(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxLength  int
+		wantPrefix string // the name without its suffix
+		wantSuffix int    // length of the suffix, without its "-"
+		wantErr    int    // Shortest of the LengthBudgetError, 0 for no error
+	}{
+		{"no limit", 0, "react-express-pizza-", 4, 0},
+		{"everything fits", 24, "react-express-pizza-", 4, 0},
+		{"codes are abbreviated before the suffix shrinks", 20, "rect-expr-pizza-", 4, 0},
+		{"the suffix shrinks once the codes are as short as they get", 15, "rc-ex-pizza-", 3, 0},
+		{"nothing fits", 14, "", 0, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(Config{
+				Seed:          1,
+				Categories:    map[string][]string{"food": {"pizza"}},
+				MaxLength:     tt.maxLength,
+				SkipDiskCheck: true,
+			})
+			sel := Selection{Frontend: "react", Backend: "express", Category: "food"}
+			name, err := g.GenerateName(MustParseLayout(DefaultEnhancedLayout), sel, SuffixMixed, 4)
+
+			if tt.wantErr > 0 {
+				var budgetErr *LengthBudgetError
+				if !errors.As(err, &budgetErr) || !errors.Is(err, ErrLengthBudget) {
+					t.Fatalf("GenerateName() = %q, %v, want a *LengthBudgetError", name, err)
+				}
+				if budgetErr.MaxLength != tt.maxLength || budgetErr.Category != "food" || budgetErr.Shortest != tt.wantErr {
+					t.Errorf("error = %+v, want MaxLength %d, Category food and Shortest %d", *budgetErr, tt.maxLength, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateName: %v", err)
+			}
+			suffix, ok := strings.CutPrefix(name, tt.wantPrefix)
+			if !ok || len(suffix) != tt.wantSuffix {
+				t.Errorf("GenerateName() = %q, want %s and a suffix of %d characters", name, tt.wantPrefix, tt.wantSuffix)
+			}
+		})
+	}
+}
+
+func TestMaxLengthNames(t *testing.T) {
+	categories := map[string][]string{
+		"food":    {"pie", "taco", "pizza", "burrito", "quesadilla"},
+		"animals": {"ox", "otter", "hippopotamus"},
+	}
+	layouts := []string{DefaultLayout, DefaultEnhancedLayout, "{word}{suffix}{suffix:numeric:3}"}
+
+	for _, layout := range layouts {
+		for _, maxLength := range []int{12, 16, 24} {
+			g := NewGenerator(Config{
+				Seed:           7,
+				Categories:     categories,
+				Frontend:       "svelte",
+				Backend:        "fastapi",
+				Layout:         MustParseLayout(layout),
+				SuffixType:     SuffixMixed,
+				SuffixLength:   6,
+				IncludeNumbers: true,
+				MaxLength:      maxLength,
+				Count:          40,
+				SkipDiskCheck:  true,
+			})
+			names, err := g.Generate()
+			if err != nil {
+				t.Errorf("%s within %d: Generate: %v", layout, maxLength, err)
+				continue
+			}
+			for _, name := range names {
+				if len(name) > maxLength {
+					t.Errorf("%s within %d: %q has %d characters", layout, maxLength, name, len(name))
+				}
+			}
+		}
+	}
+}
+
+func TestCheckCategoryWordLength(t *testing.T) {
+	g := NewGenerator(Config{
+		Categories:    map[string][]string{"food": {"pizza", "taco"}},
+		MaxWordLength: 2,
+	})
+	err := g.CheckCategory("food")
+	if err == nil || !strings.Contains(err.Error(), "no words that fit the word length limit of 2") {
+		t.Errorf("CheckCategory() error = %v, want it to name the word length limit", err)
+	}
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/aravindcm49/dir-init/internal/utils"
+)
+
+// CaseStyle controls the letter case and separator of generated names
+type CaseStyle string
+
+const (
+	CaseNone   CaseStyle = ""       // keep the name as the layout renders it
+	CaseKebab  CaseStyle = "kebab"  // pizza-night-a1b2
+	CaseSnake  CaseStyle = "snake"  // pizza_night_a1b2
+	CaseCamel  CaseStyle = "camel"  // pizzaNightA1b2
+	CasePascal CaseStyle = "pascal" // PizzaNightA1b2
+	CaseDot    CaseStyle = "dot"    // pizza.night.a1b2
+	CaseUpper  CaseStyle = "upper"  // PIZZA_NIGHT_A1B2
+)
+
+// caseSeparators holds the default separator of each case style
+var caseSeparators = map[CaseStyle]string{
+	CaseNone:   "-",
+	CaseKebab:  "-",
+	CaseSnake:  "_",
+	CaseCamel:  "",
+	CasePascal: "",
+	CaseDot:    ".",
+	CaseUpper:  "_",
+}
+
+// ParseCaseStyle converts a user supplied style name into a CaseStyle
+func ParseCaseStyle(s string) (CaseStyle, error) {
+	style := CaseStyle(strings.ToLower(strings.TrimSpace(s)))
+	if style == "none" {
+		return CaseNone, nil
+	}
+	if _, ok := caseSeparators[style]; ok {
+		return style, nil
+	}
+	return "", fmt.Errorf("unknown case style %q (use kebab, snake, camel, pascal, dot or upper)", s)
+}
+
+// ValidateSeparator checks that a separator can appear inside a directory name
+func ValidateSeparator(separator string) error {
+	if separator == "" {
+		return nil
+	}
+	if !utils.IsValidDirectoryName("a" + separator + "b") {
+		return fmt.Errorf("separator %q cannot be used in directory names", separator)
+	}
+	return nil
+}
+
+// isNameSeparator reports whether r separates the words of a rendered name
+func isNameSeparator(r rune) bool {
+	return r == '-' || r == '_' || r == '.' || r == ' '
+}
+
+// ApplyCase splits name on '-', '_', '.' and spaces and joins the words again in
+// the given style. An empty separator uses the style's default separator.
+// With CaseNone and no separator the name is returned unchanged.
+func ApplyCase(name string, style CaseStyle, separator string) string {
+	if style == CaseNone && separator == "" {
+		return name
+	}
+	if separator == "" {
+		separator = caseSeparators[style]
+	}
+
+	words := strings.FieldsFunc(name, isNameSeparator)
+	for i, w := range words {
+		switch style {
+		case CaseKebab, CaseSnake, CaseDot:
+			words[i] = strings.ToLower(w)
+		case CaseUpper:
+			words[i] = strings.ToUpper(w)
+		case CasePascal:
+			words[i] = capitalize(w)
+		case CaseCamel:
+			if i == 0 {
+				words[i] = strings.ToLower(w)
+			} else {
+				words[i] = capitalize(w)
+			}
+		}
+	}
+
+	return strings.Join(words, separator)
+}
+
+func capitalize(w string) string {
+	runes := []rune(strings.ToLower(w))
+	if len(runes) == 0 {
+		return w
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// applyStyle applies the configured case style and separator to a rendered name
+func (g *Generator) applyStyle(name string) string {
+	return ApplyCase(name, g.config.CaseStyle, g.config.Separator)
+}
@@ -0,0 +1,69 @@
+package generator
+
+import "testing"
+
+func TestApplyCase(t *testing.T) {
+	tests := []struct {
+		name      string
+		style     CaseStyle
+		separator string
+		want      string
+	}{
+		{"rct-node-pizza-night-a1b2", CaseNone, "", "rct-node-pizza-night-a1b2"},
+		{"rct-node-pizza-night-a1b2", CaseNone, "_", "rct_node_pizza_night_a1b2"},
+		{"Rct-Node-Pizza_a1B2", CaseKebab, "", "rct-node-pizza-a1b2"},
+		{"rct-node-pizza-a1b2", CaseSnake, "", "rct_node_pizza_a1b2"},
+		{"rct-node-pizza-a1b2", CaseCamel, "", "rctNodePizzaA1b2"},
+		{"rct-node-pizza-a1b2", CasePascal, "", "RctNodePizzaA1b2"},
+		{"rct-node-pizza-a1b2", CaseDot, "", "rct.node.pizza.a1b2"},
+		{"rct-node-pizza-a1b2", CaseUpper, "", "RCT_NODE_PIZZA_A1B2"},
+		{"rct-node-pizza-a1b2", CaseUpper, "-", "RCT-NODE-PIZZA-A1B2"},
+		{"pizza--night..a1b2", CaseSnake, "", "pizza_night_a1b2"},
+	}
+
+	for _, tt := range tests {
+		if got := ApplyCase(tt.name, tt.style, tt.separator); got != tt.want {
+			t.Errorf("ApplyCase(%q, %q, %q) = %q, want %q", tt.name, tt.style, tt.separator, got, tt.want)
+		}
+	}
+}
+
+func TestParseCaseStyle(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    CaseStyle
+		wantErr bool
+	}{
+		{"kebab", CaseKebab, false},
+		{" Snake ", CaseSnake, false},
+		{"none", CaseNone, false},
+		{"", CaseNone, false},
+		{"title", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCaseStyle(tt.input)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseCaseStyle(%q) = %q, %v, want %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateSeparator(t *testing.T) {
+	tests := []struct {
+		separator string
+		wantErr   bool
+	}{
+		{"", false},
+		{"-", false},
+		{"__", false},
+		{"/", true},
+		{":", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateSeparator(tt.separator); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSeparator(%q) = %v, want error %v", tt.separator, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CategoryExpr is a parsed category expression. Terms are separated by commas:
+// a name adds a category, "all" adds every category "all" picks from, and
+// "!name" removes a category. An expression of removals only starts from "all":
+//
+//	food,animals   food and animals
+//	all,!dev       every category except dev
+//	!dev,!pop      the same as all,!dev,!pop
+type CategoryExpr struct {
+	Include []string
+	Exclude []string
+}
+
+// ParseCategoryExpr splits a category expression into its terms
+func ParseCategoryExpr(s string) (CategoryExpr, error) {
+	var expr CategoryExpr
+	if strings.TrimSpace(s) == "" {
+		return CategoryExpr{Include: []string{"all"}}, nil
+	}
+
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		name, excluded := strings.CutPrefix(term, "!")
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			return CategoryExpr{}, fmt.Errorf("empty term in category expression %q", s)
+		case excluded && name == "all":
+			return CategoryExpr{}, fmt.Errorf("cannot exclude 'all' in category expression %q", s)
+		case excluded:
+			expr.Exclude = append(expr.Exclude, name)
+		default:
+			expr.Include = append(expr.Include, name)
+		}
+	}
+
+	if len(expr.Include) == 0 {
+		expr.Include = []string{"all"}
+	}
+	return expr, nil
+}
+
+// String returns the expression in its canonical form, such as "all,!dev"
+func (e CategoryExpr) String() string {
+	terms := append([]string(nil), e.Include...)
+	for _, name := range e.Exclude {
+		terms = append(terms, "!"+name)
+	}
+	return strings.Join(terms, ",")
+}
+
+// Names returns every category the expression refers to, without "all"
+func (e CategoryExpr) Names() []string {
+	var names []string
+	for _, name := range append(e.Include, e.Exclude...) {
+		if name != "all" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// categorySet is a category expression resolved against the config
+type categorySet struct {
+	names    []string            // selected categories, in expression order
+	excluded map[string]struct{} // categories removed by the expression
+}
+
+// categorySet resolves a category expression, caching the result. Unknown
+// categories are ignored here; CheckCategory reports them.
+func (g *Generator) categorySet(category string) categorySet {
+	if set, ok := g.categorySets[category]; ok {
+		return set
+	}
+
+	expr, _ := ParseCategoryExpr(category)
+	set := categorySet{excluded: make(map[string]struct{})}
+	for _, name := range expr.Exclude {
+		set.excluded[name] = struct{}{}
+	}
+
+	add := func(name string) {
+		if _, excluded := set.excluded[name]; excluded || slices.Contains(set.names, name) {
+			return
+		}
+		set.names = append(set.names, name)
+	}
+	for _, name := range expr.Include {
+		if name != "all" {
+			if _, ok := g.config.Categories[name]; ok {
+				add(name)
+			}
+			continue
+		}
+
+		all := g.config.AllCategories
+		if all == nil {
+			all = sortedKeys(g.config.Categories)
+		}
+		for _, cat := range all {
+			add(cat)
+		}
+	}
+
+	g.categorySets[category] = set
+	return set
+}
+
+// CheckCategory reports a category expression that names unknown categories,
+// selects no category, or leaves no word to pick once words are filtered
+func (g *Generator) CheckCategory(category string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	expr, err := ParseCategoryExpr(category)
+	if err != nil {
+		return err
+	}
+	for _, name := range expr.Names() {
+		if _, ok := g.config.Categories[name]; !ok {
+			return fmt.Errorf("unknown category '%s' (see 'dir-init categories')", name)
+		}
+	}
+	if len(g.categorySet(category).names) == 0 {
+		return fmt.Errorf("category expression '%s' selects no categories", category)
+	}
+
+	if g.config.Composition != nil {
+		for _, role := range g.config.Composition.roles {
+			if !g.anyWords(g.roleCategories(category, role)) {
+				return fmt.Errorf("category '%s' leaves no %s %s for composition %s",
+					category, role, g.wordsMatching(), g.config.Composition)
+			}
+		}
+	} else if !g.anyWords(g.candidateCategories(category)) {
+		return fmt.Errorf("category '%s' has no %s", category, g.wordsMatching())
+	}
+	return nil
+}
+
+// wordsMatching describes the words the word filter and word length limit accept
+func (g *Generator) wordsMatching() string {
+	words := "words"
+	if g.config.WordMatch != nil {
+		words = fmt.Sprintf("words matching '%s'", g.config.WordMatch)
+	}
+	if g.config.MaxWordLength > 0 {
+		words = fmt.Sprintf("%s that fit the word length limit of %d", words, g.config.MaxWordLength)
+	}
+	return words
+}
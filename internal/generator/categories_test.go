@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCategoryExpr(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"", "all", ""},
+		{"food", "food", ""},
+		{"food, animals", "food,animals", ""},
+		{"all,!dev", "all,!dev", ""},
+		{"!dev,! pop", "all,!dev,!pop", ""},
+		{"food,,animals", "", "empty term"},
+		{"food,!", "", "empty term"},
+		{"!all", "", "cannot exclude 'all'"},
+	}
+
+	for _, tt := range tests {
+		expr, err := ParseCategoryExpr(tt.input)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseCategoryExpr(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || expr.String() != tt.want {
+			t.Errorf("ParseCategoryExpr(%q) = %q, %v, want %q", tt.input, expr, err, tt.want)
+		}
+	}
+}
+
+func TestCategorySet(t *testing.T) {
+	g := NewGenerator(Config{
+		Seed: 1,
+		Categories: map[string][]string{
+			"food":    {"pizza"},
+			"animals": {"otter"},
+			"dev":     {"commit"},
+			"secret":  {"hush"},
+		},
+		AllCategories: []string{"food", "animals", "dev"}, // secret is hidden
+	})
+
+	tests := []struct {
+		category string
+		want     []string
+		wantErr  string
+	}{
+		{"all", []string{"food", "animals", "dev"}, ""},
+		{"dev,food", []string{"dev", "food"}, ""},
+		{"all,!dev", []string{"food", "animals"}, ""},
+		{"!food,!animals", []string{"dev"}, ""},
+		{"secret", []string{"secret"}, ""},
+		{"all,secret,food", []string{"food", "animals", "dev", "secret"}, ""},
+		{"space", nil, "unknown category 'space'"},
+		{"food,!food", nil, "selects no categories"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			err := g.CheckCategory(tt.category)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CheckCategory(%q) = %v, want %q", tt.category, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckCategory(%q): %v", tt.category, err)
+			}
+			if got := g.categorySet(tt.category).names; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("categories of %q = %v, want %v", tt.category, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PartOfSpeech tags a category with the grammatical role of its words
+type PartOfSpeech string
+
+const (
+	PosAdjective PartOfSpeech = "adjective"
+	PosNoun      PartOfSpeech = "noun"
+	PosVerb      PartOfSpeech = "verb"
+)
+
+// maxDuplicateRerolls bounds how often a role is re-picked to avoid "goofy-goofy"
+const maxDuplicateRerolls = 5
+
+// Composition is a parsed word pattern such as "adj-noun" or "adj-adj-noun".
+// Each role draws a word from the categories tagged with that part of speech;
+// categories without a tag count as nouns.
+type Composition struct {
+	source string
+	roles  []PartOfSpeech
+}
+
+// ParsePartOfSpeech converts a part of speech name or abbreviation into a PartOfSpeech
+func ParsePartOfSpeech(s string) (PartOfSpeech, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "adj", "adjective":
+		return PosAdjective, nil
+	case "noun", "n":
+		return PosNoun, nil
+	case "verb", "v":
+		return PosVerb, nil
+	}
+	return "", fmt.Errorf("unknown part of speech %q (use adjective, noun or verb)", s)
+}
+
+// ParseComposition parses a pattern of parts of speech joined by hyphens
+func ParseComposition(pattern string) (*Composition, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("composition pattern is empty")
+	}
+
+	c := &Composition{source: pattern}
+	for _, part := range strings.Split(pattern, "-") {
+		pos, err := ParsePartOfSpeech(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid composition %q: %w", pattern, err)
+		}
+		c.roles = append(c.roles, pos)
+	}
+
+	return c, nil
+}
+
+// String returns the original pattern
+func (c *Composition) String() string {
+	return c.source
+}
+
+// Roles returns the parts of speech in pattern order
+func (c *Composition) Roles() []PartOfSpeech {
+	return c.roles
+}
+
+// partOfSpeech returns the tag of a category, defaulting to noun
+func (g *Generator) partOfSpeech(category string) PartOfSpeech {
+	if pos, ok := g.config.PartsOfSpeech[category]; ok && pos != "" {
+		return pos
+	}
+	return PosNoun
+}
+
+// roleCategories returns the categories a composition role draws from.
+// Selected categories with the matching tag restrict the role to them; otherwise
+// every category with the tag that the selection does not exclude is used.
+func (g *Generator) roleCategories(category string, role PartOfSpeech) []string {
+	set := g.categorySet(category)
+
+	var candidates []string
+	for _, cat := range set.names {
+		if g.partOfSpeech(cat) == role && len(g.config.Categories[cat]) > 0 {
+			candidates = append(candidates, cat)
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates
+	}
+
+	for _, cat := range sortedKeys(g.config.Categories) {
+		if _, excluded := set.excluded[cat]; excluded {
+			continue
+		}
+		if g.partOfSpeech(cat) == role && len(g.words(cat)) > 0 {
+			candidates = append(candidates, cat)
+		}
+	}
+	return candidates
+}
+
+// composeWord builds a phrase following the composition pattern. The returned
+// category is the one the last noun came from, or the last role's category.
+func (g *Generator) composeWord(category string) (string, string) {
+	words := make([]string, 0, len(g.config.Composition.roles))
+	used := make(map[string]struct{})
+	resultCategory := ""
+
+	// Share the word limit between the roles, keeping room for the shortest
+	// word of every role still to come and the hyphens between them
+	limit := g.wordLimit
+	defer func() { g.wordLimit = limit }()
+	remaining := limit
+
+	for i, role := range g.config.Composition.roles {
+		if limit > 0 {
+			g.wordLimit = 0
+			reserved := 0
+			for _, next := range g.config.Composition.roles[i+1:] {
+				reserved += 1 + g.shortestIn(g.roleCategories(category, next))
+			}
+			g.wordLimit = max(remaining-reserved, 1)
+		}
+
+		candidates := g.roleCategories(category, role)
+
+		var cat, word string
+		for try := 0; try <= maxDuplicateRerolls; try++ {
+			cat, word = g.pickWordFrom(candidates)
+			if _, dup := used[word]; !dup {
+				break
+			}
+		}
+
+		used[word] = struct{}{}
+		words = append(words, word)
+		remaining -= len(word) + 1
+		if role == PosNoun || resultCategory == "" {
+			resultCategory = cat
+		}
+	}
+
+	return resultCategory, strings.Join(words, "-")
+}
+
+// compositionSpace counts the distinct phrases a composition can produce
+func (g *Generator) compositionSpace(category string) uint64 {
+	total := uint64(1)
+	for _, role := range g.config.Composition.roles {
+		words := make(map[string]struct{})
+		for _, cat := range g.roleCategories(category, role) {
+			for _, w := range g.words(cat) {
+				words[w] = struct{}{}
+			}
+		}
+		if len(words) == 0 {
+			words["folder"] = struct{}{}
+		}
+		total = mulSaturating(total, uint64(len(words)))
+	}
+	return total
+}
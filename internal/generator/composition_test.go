@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseComposition(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []PartOfSpeech
+		wantErr bool
+	}{
+		{"adj-noun", []PartOfSpeech{PosAdjective, PosNoun}, false},
+		{"Adjective-adj-N", []PartOfSpeech{PosAdjective, PosAdjective, PosNoun}, false},
+		{"verb-noun", []PartOfSpeech{PosVerb, PosNoun}, false},
+		{"", nil, true},
+		{"adj-thing", nil, true},
+		{"adj--noun", nil, true},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseComposition(tt.pattern)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseComposition(%q) error = %v, want error %v", tt.pattern, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (!reflect.DeepEqual(c.Roles(), tt.want) || c.String() != tt.pattern) {
+			t.Errorf("ParseComposition(%q) = %v (%s), want %v", tt.pattern, c.Roles(), c, tt.want)
+		}
+	}
+}
+
+func compositionConfig(pattern string) Config {
+	return Config{
+		Seed: 4,
+		Categories: map[string][]string{
+			"moods":   {"goofy", "sleepy", "brave"},
+			"colors":  {"red", "teal"},
+			"animals": {"penguin", "otter"},
+			"food":    {"pizza"},
+		},
+		PartsOfSpeech: map[string]PartOfSpeech{"moods": PosAdjective, "colors": PosAdjective},
+		Composition:   mustParseComposition(pattern),
+		Layout:        MustParseLayout("{word}"),
+		SkipDiskCheck: true,
+	}
+}
+
+func mustParseComposition(pattern string) *Composition {
+	c, err := ParseComposition(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func TestComposeWord(t *testing.T) {
+	adjectives := []string{"goofy", "sleepy", "brave", "red", "teal"}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		category string
+		nouns    []string
+	}{
+		{"untagged categories are nouns", "adj-noun", "all", []string{"penguin", "otter", "pizza"}},
+		{"selected category restricts the noun", "adj-noun", "food", []string{"pizza"}},
+		{"two adjectives", "adj-adj-noun", "animals", []string{"penguin", "otter"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(compositionConfig(tt.pattern))
+			for i := 0; i < 50; i++ {
+				category, phrase := g.composeWord(tt.category)
+				words := strings.Split(phrase, "-")
+				if len(words) != len(g.config.Composition.Roles()) {
+					t.Fatalf("composeWord() = %q, want %d words", phrase, len(g.config.Composition.Roles()))
+				}
+
+				noun := words[len(words)-1]
+				if !slices.Contains(tt.nouns, noun) {
+					t.Errorf("composeWord() = %q, want a noun out of %v", phrase, tt.nouns)
+				}
+				for _, adj := range words[:len(words)-1] {
+					if !slices.Contains(adjectives, adj) {
+						t.Errorf("composeWord() = %q, want adjectives out of %v", phrase, adjectives)
+					}
+				}
+				if len(words) == 3 && words[0] == words[1] {
+					t.Errorf("composeWord() = %q repeats a word", phrase)
+				}
+				if !slices.Contains(g.config.Categories[category], noun) {
+					t.Errorf("composeWord() category = %q, want the category of %q", category, noun)
+				}
+			}
+		})
+	}
+}
+
+func TestCompositionSpace(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		category string
+		want     uint64
+	}{
+		{"adj-noun", "all", 5 * 3},
+		{"adj-noun", "food", 5 * 1},
+		{"adj-adj-noun", "animals", 5 * 5 * 2},
+		{"verb-noun", "all", 1 * 3}, // no verbs, so the role falls back to "folder"
+	}
+
+	for _, tt := range tests {
+		g := NewGenerator(compositionConfig(tt.pattern))
+		if got := g.compositionSpace(tt.category); got != tt.want {
+			t.Errorf("compositionSpace(%s, %s) = %d, want %d", tt.pattern, tt.category, got, tt.want)
+		}
+	}
+}
+
+func TestCompositionMaxLength(t *testing.T) {
+	config := compositionConfig("adj-noun")
+	config.MaxLength = 9 // "red-otter", "teal-otter" is too long
+	config.Count = 2
+	g := NewGenerator(config)
+
+	names, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, name := range names {
+		if len(name) > 9 {
+			t.Errorf("%q has %d characters, want at most 9", name, len(name))
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/aravindcm49/dir-init/internal/utils"
+)
+
+// emojiFor returns the emoji of a word, or "" if the table has none. For a
+// composed phrase such as "goofy-penguin" the last word with an emoji wins.
+func (g *Generator) emojiFor(category, word string) string {
+	if emoji := g.config.Emojis[category][word]; emoji != "" {
+		return emoji
+	}
+
+	parts := strings.Split(word, "-")
+	for i := len(parts) - 1; i >= 0; i-- {
+		for _, cat := range sortedEmojiKeys(g.config.Emojis) {
+			if emoji := g.config.Emojis[cat][parts[i]]; emoji != "" {
+				return emoji
+			}
+		}
+	}
+	return ""
+}
+
+// decorate puts the emoji of the picked word in front of a styled name, as in
+// "🍕-pizza-a1b2". The plain name is kept when there is no emoji for the word,
+// or when the decorated name is rejected by IsValidDirectoryName, the
+// portability profile or the length limit.
+func (g *Generator) decorate(name string, picked Selection) string {
+	if !g.config.UseEmojis {
+		return name
+	}
+
+	emoji := g.emojiFor(picked.Category, picked.Word)
+	if emoji == "" {
+		return name
+	}
+
+	separator := g.config.Separator
+	if separator == "" {
+		separator = caseSeparators[g.config.CaseStyle]
+	}
+	decorated := emoji + separator + name
+
+	if !utils.IsPortableName(decorated, g.portability()) {
+		return name
+	}
+	if g.config.MaxLength > 0 && len(decorated) > g.config.MaxLength {
+		return name
+	}
+	return decorated
+}
+
+// undecorated removes the emoji and separator that decorate puts in front of a
+// name, as well as any other leading symbols
+func undecorated(name string) string {
+	return strings.TrimLeftFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// portability returns the configured portability profile, defaulting to native
+func (g *Generator) portability() utils.Portability {
+	if g.config.Portability == "" {
+		return utils.PortabilityNative
+	}
+	return g.config.Portability
+}
+
+// digitSuffixTypes are the suffix types that always contain digits
+var digitSuffixTypes = map[SuffixType]bool{
+	SuffixNumeric:   true,
+	SuffixTimestamp: true,
+	SuffixULID:      true,
+	SuffixUUIDv7:    true,
+	SuffixDate:      true,
+	SuffixDateTime:  true,
+	SuffixHex:       true,
+	SuffixSequence:  true,
+}
+
+// checkDigits rejects layouts whose suffixes need digits when IncludeNumbers is off
+func (g *Generator) checkDigits(layout *Layout, suffixType SuffixType, length int) error {
+	if g.config.IncludeNumbers {
+		return nil
+	}
+	for _, t := range layout.tokens {
+		if t.Kind != TokenSuffix {
+			continue
+		}
+		if st, _ := suffixTokenConfig(t, suffixType, length); digitSuffixTypes[st] {
+			return fmt.Errorf("suffix type %s always contains digits; allow numbers or use alpha, mixed, base32 or pronounceable", st)
+		}
+	}
+	return nil
+}
+
+func sortedEmojiKeys(tables map[string]map[string]string) []string {
+	keys := make(map[string][]string, len(tables))
+	for k := range tables {
+		keys[k] = nil
+	}
+	return sortedKeys(keys)
+}
@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+)
+
+// maxBlockedRerolls bounds how often a word or suffix is re-rolled because the blocklist matched it
+const maxBlockedRerolls = 20
+
+// errBlocked makes GenerateName start over when a suffix keeps matching the blocklist
+var errBlocked = errors.New("name matches the blocklist")
+
+// safeForWorkWords are built-in words that are unwelcome in client-facing names
+var safeForWorkWords = []string{
+	"assassin", "necromancer", "executioner", "killer", "murder", "slave",
+	"bomb", "drug", "drunk", "gun", "hitman", "nazi", "poison", "sniper",
+}
+
+// safeForWorkSubstrings are built-in substrings that random suffixes must not spell.
+// They are not applied to words, where they would also reject "bass" or "class".
+var safeForWorkSubstrings = []string{
+	"ass", "butt", "cock", "cok", "cum", "cunt", "dick", "dik", "fag", "fck",
+	"fuk", "fuck", "fuq", "jizz", "kkk", "nazi", "nig", "piss", "porn", "rape",
+	"sex", "shit", "sht", "slut", "tit", "twat", "wank", "wtf", "xxx",
+}
+
+// Blocklist rejects words and suffixes. Words match whole words, substrings
+// match anywhere in a word or suffix; both ignore case.
+type Blocklist struct {
+	words            map[string]struct{}
+	substrings       []string
+	suffixSubstrings []string // substrings only checked in suffixes
+	safeForWork      bool
+}
+
+// NewBlocklist builds a blocklist from user supplied words and substrings,
+// adding the built-in safe for work lists when safeForWork is set
+func NewBlocklist(words, substrings []string, safeForWork bool) *Blocklist {
+	b := &Blocklist{words: make(map[string]struct{}), safeForWork: safeForWork}
+
+	add := func(list *[]string, values []string) {
+		for _, v := range values {
+			if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+				*list = append(*list, v)
+			}
+		}
+	}
+
+	var allWords []string
+	add(&allWords, words)
+	add(&b.substrings, substrings)
+	if safeForWork {
+		add(&allWords, safeForWorkWords)
+		add(&b.suffixSubstrings, safeForWorkSubstrings)
+	}
+	for _, w := range allWords {
+		b.words[w] = struct{}{}
+	}
+
+	return b
+}
+
+// SafeForWork reports whether the built-in safe for work lists are included
+func (b *Blocklist) SafeForWork() bool {
+	return b != nil && b.safeForWork
+}
+
+// BlocksWord reports whether a word, or any part of a hyphenated phrase, is blocked
+func (b *Blocklist) BlocksWord(word string) bool {
+	if b == nil {
+		return false
+	}
+
+	word = strings.ToLower(word)
+	if _, ok := b.words[word]; ok {
+		return true
+	}
+	for _, part := range strings.Split(word, "-") {
+		if _, ok := b.words[part]; ok {
+			return true
+		}
+	}
+	return containsAny(word, b.substrings)
+}
+
+// BlocksSuffix reports whether a suffix contains a blocked substring
+func (b *Blocklist) BlocksSuffix(suffix string) bool {
+	if b == nil {
+		return false
+	}
+
+	suffix = strings.ToLower(suffix)
+	return containsAny(suffix, b.substrings) || containsAny(suffix, b.suffixSubstrings)
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rejections counts the words and suffixes re-rolled because the blocklist matched them
+type Rejections struct {
+	Words    int
+	Suffixes int
+}
+
+// Rejections returns how many words and suffixes the blocklist rejected so far
+func (g *Generator) Rejections() Rejections {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.rejections
+}
+
+// filteredSuffix generates a suffix, re-rolling it while the blocklist matches.
+// Suffixes made of dates or time only change with the clock and are not re-rolled.
+func (g *Generator) filteredSuffix(suffixType SuffixType, length int) (string, error) {
+	suffix := g.generateSuffixWithConfig(suffixType, length)
+	switch suffixType {
+	case SuffixTimestamp, SuffixDate, SuffixDateTime:
+		return suffix, nil
+	}
+
+	for try := 0; g.config.Blocklist.BlocksSuffix(suffix); try++ {
+		g.rejections.Suffixes++
+		if try == maxBlockedRerolls {
+			return "", errBlocked
+		}
+		suffix = g.generateSuffixWithConfig(suffixType, length)
+	}
+	return suffix, nil
+}
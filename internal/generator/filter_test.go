@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlocklist(t *testing.T) {
+	b := NewBlocklist([]string{" Taco ", ""}, []string{"XQ"}, true)
+
+	words := []struct {
+		word string
+		want bool
+	}{
+		{"pizza", false},
+		{"taco", true},
+		{"TACO", true},
+		{"spicy-taco", true},
+		{"tacos", false},
+		{"exquisite", true},
+		{"sniper", true},
+		{"class", false}, // built-in substrings only apply to suffixes
+		{"bass", false},
+	}
+	for _, tt := range words {
+		if got := b.BlocksWord(tt.word); got != tt.want {
+			t.Errorf("BlocksWord(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+
+	suffixes := []struct {
+		suffix string
+		want   bool
+	}{
+		{"a1b2", false},
+		{"x9sEx", true},
+		{"xq01", true},
+		{"taco", false}, // words only match whole words
+	}
+	for _, tt := range suffixes {
+		if got := b.BlocksSuffix(tt.suffix); got != tt.want {
+			t.Errorf("BlocksSuffix(%q) = %v, want %v", tt.suffix, got, tt.want)
+		}
+	}
+
+	if !b.SafeForWork() {
+		t.Errorf("SafeForWork() = false, want true")
+	}
+	var none *Blocklist
+	if none.BlocksWord("sniper") || none.BlocksSuffix("sex") || none.SafeForWork() {
+		t.Errorf("a nil blocklist blocks names")
+	}
+	if NewBlocklist(nil, nil, false).BlocksSuffix("sex") {
+		t.Errorf("BlocksSuffix() without safe for work uses the built-in list")
+	}
+}
+
+func TestGenerateSkipsBlockedWords(t *testing.T) {
+	g := NewGenerator(Config{
+		Seed:          2,
+		Categories:    map[string][]string{"roles": {"sniper", "wizard", "killer", "bard"}},
+		Blocklist:     NewBlocklist([]string{"wizard"}, nil, true),
+		Layout:        MustParseLayout("{word}{suffix}"),
+		SuffixType:    SuffixAlpha,
+		SuffixLength:  3,
+		Count:         100,
+		SkipDiskCheck: true,
+	})
+
+	names, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, name := range names {
+		word, suffix, _ := strings.Cut(name, "-")
+		if word != "bard" {
+			t.Errorf("%q uses a blocked word", name)
+		}
+		if g.config.Blocklist.BlocksSuffix(suffix) {
+			t.Errorf("%q uses a blocked suffix", name)
+		}
+	}
+	if r := g.Rejections(); r.Words == 0 {
+		t.Errorf("Rejections() = %+v, want rejected words", r)
+	}
+}
+
+func TestGenerateAllWordsBlocked(t *testing.T) {
+	// Every word is blocked, so the pick falls back to the unfiltered words
+	// instead of re-rolling forever
+	g := NewGenerator(Config{
+		Seed:          2,
+		Categories:    map[string][]string{"roles": {"sniper"}},
+		Blocklist:     NewBlocklist(nil, nil, true),
+		Count:         1,
+		SkipDiskCheck: true,
+	})
+	if _, err := g.Generate(); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if r := g.Rejections(); r.Words != maxBlockedRerolls {
+		t.Errorf("Rejections() = %+v, want %d rejected words", r, maxBlockedRerolls)
+	}
+}
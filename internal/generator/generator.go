@@ -1,169 +1,256 @@
 package generator
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aravindcm49/dir-init/internal/utils"
 )
 
-type SuffixType string
-
-const (
-	SuffixAlpha     SuffixType = "alpha"
-	SuffixNumeric   SuffixType = "numeric"
-	SuffixMixed     SuffixType = "mixed"
-	SuffixTimestamp SuffixType = "timestamp"
-)
-
 type Config struct {
-	Category       string
-	SuffixType     SuffixType
-	SuffixLength   int
-	IncludeNumbers bool
-	UseEmojis      bool
-	Count          int
-	Seed           int64
-	Categories     map[string][]string
+	Category        string
+	SuffixType      SuffixType
+	SuffixLength    int
+	IncludeNumbers  bool // allow digits in suffixes
+	UseEmojis       bool // prefix names with the emoji of their word when one is configured
+	Count           int
+	Seed            int64
+	Categories      map[string][]string
+	AllCategories   []string                      // categories "all" picks from, nil means every category
+	CategoryWeights map[string]float64            // used by SamplingWeighted, missing means 1
+	WordWeights     map[string]map[string]float64 // category -> word -> weight, missing means 1
+	Sampling        SamplingStrategy
+	PartsOfSpeech   map[string]PartOfSpeech // category -> tag, untagged categories are nouns
+	Composition     *Composition            // nil picks a single word
+	Frontend        string
+	Backend         string
+	Layout          *Layout                      // nil selects DefaultLayout or DefaultEnhancedLayout
+	TargetDir       string                       // directory checked for existing names, "" means the working directory
+	MaxAttempts     int                          // re-rolls allowed per name before giving up
+	SkipDiskCheck   bool                         // only avoid names issued in this run or passed to Reserve
+	Now             time.Time                    // clock for {date} tokens and time-based suffixes, zero means time.Now
+	SequenceStarts  map[string]int               // highest existing number per sequence prefix, skips scanning TargetDir
+	CaseStyle       CaseStyle                    // applied to the rendered name before validation
+	Separator       string                       // replaces the separators between words, "" uses the style default
+	MaxLength       int                          // longest name in bytes, 0 means unlimited
+	MaxWordLength   int                          // longest word drawn from a category, 0 means unlimited
+	MaxCodeLength   int                          // frontend and backend codes are abbreviated to this length, 0 keeps them whole
+	Blocklist       *Blocklist                   // words and suffixes to re-roll, nil allows everything
+	SuffixDigits    int                          // trailing digits of pronounceable suffixes, part of SuffixLength
+	Emojis          map[string]map[string]string // category -> word -> emoji, used by UseEmojis
+	Portability     utils.Portability            // rules names must follow, "" means native
+	Alliteration    Alliteration                 // pick words starting with the initial of the frontend or backend
+	WordMatch       *regexp.Regexp               // words must match, nil accepts every word
+	FrontendCodes   []string                     // known frontend codes, used by ParseName
+	BackendCodes    []string                     // known backend codes, used by ParseName
 }
 
+// Generator is safe for concurrent use: its exported methods serialize on mu,
+// which keeps the random source, the issued names and the clock consistent.
 type Generator struct {
+	mu sync.Mutex
+
 	config Config
 	rand   *rand.Rand
+	issued map[string]struct{} // names handed out by this generator
+	onDisk map[string]struct{} // names found to already exist in TargetDir
+	taken  map[string]nameSet  // issued or existing names each name space has produced, see spaceKey
+	ticks  int64               // milliseconds the clock has advanced for time-based suffixes
+	last   time.Time           // last time handed out by tick
+
+	wordLimit   int        // longest word the name being rendered has room for, 0 means unlimited
+	skipBlocked bool       // leave blocked words out of the pick instead of re-rolling
+	initials    string     // letters words must start with while alliterating, "" allows any
+	rejections  Rejections // words and suffixes rejected by the blocklist
+
+	sequences      map[string]int // last number issued per sequence prefix
+	sequenceStarts map[string]int // highest number found on disk per sequence prefix
+
+	categorySets map[string]categorySet // resolved category expressions
 }
 
 func NewGenerator(config Config) *Generator {
-	r := rand.New(rand.NewSource(config.Seed))
+	// Resolve a zero seed up front so the effective seed can be recorded and replayed
 	if config.Seed == 0 {
-		r.Seed(time.Now().UnixNano())
+		config.Seed = time.Now().UnixNano()
 	}
+	r := rand.New(rand.NewSource(config.Seed))
 
 	return &Generator{
 		config: config,
 		rand:   r,
+		issued: make(map[string]struct{}),
+		onDisk: make(map[string]struct{}),
+		taken:  make(map[string]nameSet),
+
+		sequences:      make(map[string]int),
+		sequenceStarts: make(map[string]int),
+		categorySets:   make(map[string]categorySet),
 	}
 }
 
-func (g *Generator) Generate() []string {
-	if g.config.Count <= 0 {
-		g.config.Count = 1
+// Seed returns the effective random seed, which reproduces this generator's output
+func (g *Generator) Seed() int64 {
+	return g.config.Seed
+}
+
+// Reserve marks names as unavailable, as if they already existed in the target directory
+func (g *Generator) Reserve(names ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, name := range names {
+		g.onDisk[name] = struct{}{}
 	}
+}
 
-	names := make([]string, 0, g.config.Count)
+// Reserved returns the sorted names that were skipped because they already existed
+func (g *Generator) Reserved() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	for i := 0; i < g.config.Count; i++ {
-		category := g.selectCategory()
-		prefix := g.selectWordFromCategory(category)
-		suffix := g.generateSuffix()
-		name := fmt.Sprintf("%s%s", prefix, suffix)
+	names := make([]string, 0, len(g.onDisk))
+	for name := range g.onDisk {
 		names = append(names, name)
 	}
-
+	sort.Strings(names)
 	return names
 }
 
-func (g *Generator) selectCategory() string {
-	if g.config.Category == "all" || g.config.Category == "" {
-		categories := []string{"tech", "food", "animals", "pop", "silly", "dev"}
-		return categories[g.rand.Intn(len(categories))]
+// now returns the generator clock
+func (g *Generator) now() time.Time {
+	if g.config.Now.IsZero() {
+		return time.Now()
 	}
-	return g.config.Category
+	return g.config.Now
 }
 
-func (g *Generator) selectWordFromCategory(category string) string {
-	var words []string
+func (g *Generator) Generate() ([]string, error) {
+	count := max(g.config.Count, 1)
 
-	if g.config.Categories != nil {
-		words = g.config.Categories[category]
+	layout := g.layoutOrDefault(DefaultLayout)
+	sel := Selection{
+		Frontend: g.config.Frontend,
+		Backend:  g.config.Backend,
+		Category: g.config.Category,
 	}
 
-	if len(words) == 0 {
-		// Fallback if category not found or empty
-		return "folder"
-	}
+	names := make([]string, 0, count)
 
-	if len(words) == 0 {
-		return "folder"
+	for i := 0; i < count; i++ {
+		name, err := g.GenerateName(layout, sel, g.config.SuffixType, g.config.SuffixLength)
+		if err != nil {
+			return names, err
+		}
+		names = append(names, name)
 	}
 
-	return words[g.rand.Intn(len(words))]
+	return names, nil
 }
 
-func (g *Generator) generateSuffix() string {
-	switch g.config.SuffixType {
-	case SuffixAlpha:
-		return g.generateAlphaSuffix()
-	case SuffixNumeric:
-		return g.generateNumericSuffix()
-	case SuffixMixed:
-		return g.generateMixedSuffix()
-	case SuffixTimestamp:
-		return g.generateTimestampSuffix()
-	default:
-		return g.generateMixedSuffix()
+// layoutOrDefault returns the configured layout, or the given built-in layout when none is set
+func (g *Generator) layoutOrDefault(fallback string) *Layout {
+	if g.config.Layout != nil {
+		return g.config.Layout
 	}
+	return MustParseLayout(fallback)
 }
 
-func (g *Generator) generateAlphaSuffix() string {
-	length := g.config.SuffixLength
-	if length < 3 {
-		length = 3
-	} else if length > 8 {
-		length = 8
-	}
-
-	chars := "abcdefghijklmnopqrstuvwxyz"
-	var suffix strings.Builder
+// EnhancedLayout returns the layout used for frontend/backend names
+func (g *Generator) EnhancedLayout() *Layout {
+	return g.layoutOrDefault(DefaultEnhancedLayout)
+}
 
-	for i := 0; i < length; i++ {
-		suffix.WriteByte(chars[g.rand.Intn(len(chars))])
-	}
+// GenerateName renders a single validated name from a layout and selection.
+// Names that were already issued by this generator or exist in the target
+// directory are re-rolled; an *ExhaustedError is returned when the retry budget runs out.
+func (g *Generator) GenerateName(layout *Layout, sel Selection, suffixType SuffixType, length int) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	return "-" + suffix.String()
+	return g.generateName(layout, sel, suffixType, length)
 }
 
-func (g *Generator) generateNumericSuffix() string {
-	length := g.config.SuffixLength
-	if length < 1 {
-		length = 1
-	} else if length > 6 {
-		length = 6
+// generateName implements GenerateName; the caller holds g.mu
+func (g *Generator) generateName(layout *Layout, sel Selection, suffixType SuffixType, length int) (string, error) {
+	maxAttempts := g.config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
 	}
 
-	max := intPow(10, length) - 1
-	min := intPow(10, length-1)
-
-	if length == 1 {
-		min = 0
+	if err := g.checkDigits(layout, suffixType, length); err != nil {
+		return "", err
 	}
 
-	num := g.rand.Intn(max-min+1) + min
-	return fmt.Sprintf("-%d", num)
-}
+	g.initials = g.alliterationInitials(sel)
+	defer func() { g.initials = "" }()
 
-func (g *Generator) generateMixedSuffix() string {
-	length := g.config.SuffixLength
-	if length < 3 {
-		length = 3
-	} else if length > 8 {
-		length = 8
-	}
+	key := spaceKey(layout, sel, suffixType, length)
+	tooLong, shortest := 0, 0
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		name, picked, err := g.render(layout, sel, suffixType, length, g.now())
+		if errors.Is(err, errBlocked) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		name = g.applyStyle(name)
+
+		// A separator longer than the one it replaces can still push the name over the limit
+		if g.config.MaxLength > 0 && len(name) > g.config.MaxLength {
+			if tooLong == 0 || len(name) < shortest {
+				shortest = len(name)
+			}
+			tooLong++
+			continue
+		}
 
-	chars := "abcdefghijklmnopqrstuvwxyz0123456789"
-	var suffix strings.Builder
+		if !utils.IsValidDirectoryName(name) {
+			return "", fmt.Errorf("generated name '%s' is not valid for filesystem", name)
+		}
+		if !utils.IsPortableName(name, g.portability()) {
+			return "", fmt.Errorf("generated name '%s' is not allowed by the %s portability profile", name, g.portability())
+		}
+		name = g.decorate(name, picked)
 
-	for i := 0; i < length; i++ {
-		suffix.WriteByte(chars[g.rand.Intn(len(chars))])
+		if g.isTaken(name) {
+			g.markTaken(key, name)
+			continue
+		}
+
+		g.issued[name] = struct{}{}
+		g.markTaken(key, name)
+		return name, nil
 	}
 
-	return "-" + suffix.String()
+	if tooLong == maxAttempts {
+		category := sel.Category
+		if category == "" {
+			category = "all"
+		}
+		return "", &LengthBudgetError{MaxLength: g.config.MaxLength, Category: category, Shortest: shortest}
+	}
+
+	return "", g.newExhaustedError(layout, sel, suffixType, length, maxAttempts)
 }
 
-func (g *Generator) generateTimestampSuffix() string {
-	timestamp := time.Now().Unix()
-	return fmt.Sprintf("-%d", timestamp%100000000)
+func (g *Generator) selectWordFromCategory(category string) string {
+	words := g.words(category)
+
+	if len(words) == 0 {
+		// Fallback if category not found or empty
+		return "folder"
+	}
+
+	return words[g.rand.Intn(len(words))]
 }
 
 func intPow(base, exp int) int {
@@ -176,50 +263,13 @@ func intPow(base, exp int) int {
 
 // GenerateSingleName generates a single directory name with the specified category and suffix type
 func (g *Generator) GenerateSingleName(category string, suffixType SuffixType, length int) (string, error) {
-	// Create a temporary config for this single generation
-	tempConfig := Config{
-		Category:       category,
-		SuffixType:     suffixType,
-		SuffixLength:   length,
-		IncludeNumbers: true,
-		UseEmojis:      false,
-		Count:          1,
-		Seed:           g.rand.Int63(),
-	}
-
-	// Create a new generator with this config
-	tempGenerator := &Generator{
-		config: tempConfig,
-		rand:   g.rand,
-	}
-
-	// Generate the name
-	category = tempGenerator.selectCategory()
-	prefix := tempGenerator.selectWordFromCategory(category)
-	suffix := tempGenerator.generateSuffix()
-	name := fmt.Sprintf("%s%s", prefix, suffix)
-
-	// Validate the name
-	if !utils.IsValidDirectoryName(name) {
-		return "", fmt.Errorf("generated name '%s' is not valid for filesystem", name)
-	}
-
-	return name, nil
+	return g.GenerateName(g.layoutOrDefault(DefaultLayout), Selection{Category: category}, suffixType, length)
 }
 
 // CreateDirectory generates and creates a single directory
 func (g *Generator) CreateDirectory(category string, suffixType SuffixType, length int) error {
-	name, err := g.GenerateSingleName(category, suffixType, length)
-	if err != nil {
-		return err
-	}
-
-	err = utils.CreateDirectory(name)
-	if err != nil {
-		return fmt.Errorf("failed to create directory '%s': %v", name, err)
-	}
-
-	return nil
+	_, err := g.CreateName(g.layoutOrDefault(DefaultLayout), Selection{Category: category}, suffixType, length)
+	return err
 }
 
 // CreateDirectories generates and creates multiple directories
@@ -228,25 +278,21 @@ func (g *Generator) CreateDirectories(category string, suffixType SuffixType, le
 		return nil, fmt.Errorf("count must be positive")
 	}
 
-	if count > 20 {
-		return nil, fmt.Errorf("cannot create more than 20 directories at once")
-	}
-
-	names := make([]string, 0, count)
+	layout := g.layoutOrDefault(DefaultLayout)
 	createdNames := make([]string, 0, count)
 
 	for i := 0; i < count; i++ {
-		name, err := g.GenerateSingleName(category, suffixType, length)
-		if err != nil {
-			continue
+		name, err := g.CreateName(layout, Selection{Category: category}, suffixType, length)
+		if errors.Is(err, ErrNameSpaceExhausted) {
+			if len(createdNames) == 0 {
+				return nil, err
+			}
+			return createdNames, err
 		}
-
-		err = utils.CreateDirectory(name)
 		if err != nil {
 			continue
 		}
 
-		names = append(names, name)
 		createdNames = append(createdNames, name)
 	}
 
@@ -257,51 +303,36 @@ func (g *Generator) CreateDirectories(category string, suffixType SuffixType, le
 	return createdNames, nil
 }
 
-// GenerateEnhancedName generates names with the new enhanced format: {techstack}-{framework}-{category}-{suffix}
-func (g *Generator) GenerateEnhancedName(techStack, framework, category string, suffixType SuffixType, length int) (string, error) {
-	// Generate prefix part: {techstack}-{framework}
-	prefix := fmt.Sprintf("%s-%s", techStack, framework)
-
-	// Generate category word
-	categoryWord := g.selectWordFromCategory(category)
-
-	// Generate suffix
-	suffix := g.generateSuffixWithConfig(suffixType, length)
-
-	// Combine all parts: {techstack}-{framework}-{categoryword}-{suffix}
-	name := fmt.Sprintf("%s-%s%s", prefix, categoryWord, suffix)
-
-	// Validate the name
-	if !utils.IsValidDirectoryName(name) {
-		return "", fmt.Errorf("generated name '%s' is not valid for filesystem", name)
+// targetDirOrDefault returns the target directory, defaulting to the working directory
+func (g *Generator) targetDirOrDefault() string {
+	if g.config.TargetDir == "" {
+		return "."
 	}
+	return g.config.TargetDir
+}
 
-	return name, nil
+// targetPath joins a generated name onto the target directory
+func (g *Generator) targetPath(name string) string {
+	if g.config.TargetDir == "" {
+		return name
+	}
+	return filepath.Join(g.config.TargetDir, name)
 }
 
-// generateSuffixWithConfig generates suffix with specific configuration
-func (g *Generator) generateSuffixWithConfig(suffixType SuffixType, length int) string {
-	switch suffixType {
-	case SuffixAlpha:
-		return g.generateAlphaSuffixWithLength(length)
-	case SuffixNumeric:
-		return g.generateNumericSuffixWithLength(length)
-	case SuffixMixed:
-		return g.generateMixedSuffixWithLength(length)
-	case SuffixTimestamp:
-		return g.generateTimestampSuffix()
-	default:
-		return g.generateMixedSuffixWithLength(length)
+// GenerateEnhancedName generates a name for a frontend/backend selection using the configured
+// layout, or DefaultEnhancedLayout when none is set
+func (g *Generator) GenerateEnhancedName(frontend, backend, category string, suffixType SuffixType, length int) (string, error) {
+	sel := Selection{
+		Frontend: frontend,
+		Backend:  backend,
+		Category: category,
 	}
+	return g.GenerateName(g.EnhancedLayout(), sel, suffixType, length)
 }
 
 // generateAlphaSuffixWithLength generates alphabetic suffix with specific length
 func (g *Generator) generateAlphaSuffixWithLength(length int) string {
-	if length < 3 {
-		length = 3
-	} else if length > 8 {
-		length = 8
-	}
+	length = clampSuffixLength(SuffixAlpha, length)
 
 	chars := "abcdefghijklmnopqrstuvwxyz"
 	var suffix strings.Builder
@@ -313,13 +344,53 @@ func (g *Generator) generateAlphaSuffixWithLength(length int) string {
 	return "-" + suffix.String()
 }
 
+// consonants and vowels make up pronounceable suffixes; c, q, w, x and y are
+// left out because they are easy to misspell when read aloud
+const (
+	consonants = "bdfghjklmnprstvz"
+	vowels     = "aeiou"
+)
+
+// generatePronounceableSuffixWithLength generates a suffix of alternating
+// consonants and vowels, such as "-bako" or "-tirun". When SuffixDigits is set,
+// that many of the length characters are digits at the end, as in "-bako42".
+func (g *Generator) generatePronounceableSuffixWithLength(length int) string {
+	length = clampSuffixLength(SuffixPronounceable, length)
+	letters, digits := pronounceableSplit(length, g.suffixDigits())
+
+	var suffix strings.Builder
+	for i := 0; i < letters; i++ {
+		if i%2 == 0 {
+			suffix.WriteByte(consonants[g.rand.Intn(len(consonants))])
+		} else {
+			suffix.WriteByte(vowels[g.rand.Intn(len(vowels))])
+		}
+	}
+	for i := 0; i < digits; i++ {
+		suffix.WriteByte(byte('0' + g.rand.Intn(10)))
+	}
+
+	return "-" + suffix.String()
+}
+
+// suffixDigits returns the number of trailing digits of pronounceable suffixes
+func (g *Generator) suffixDigits() int {
+	if !g.config.IncludeNumbers {
+		return 0
+	}
+	return g.config.SuffixDigits
+}
+
+// pronounceableSplit divides a suffix length between letters and trailing
+// digits, keeping at least one syllable of letters
+func pronounceableSplit(length, digits int) (int, int) {
+	digits = max(min(digits, length-2), 0)
+	return length - digits, digits
+}
+
 // generateNumericSuffixWithLength generates numeric suffix with specific length
 func (g *Generator) generateNumericSuffixWithLength(length int) string {
-	if length < 1 {
-		length = 1
-	} else if length > 6 {
-		length = 6
-	}
+	length = clampSuffixLength(SuffixNumeric, length)
 
 	max := intPow(10, length) - 1
 	min := intPow(10, length-1)
@@ -334,13 +405,12 @@ func (g *Generator) generateNumericSuffixWithLength(length int) string {
 
 // generateMixedSuffixWithLength generates mixed suffix with specific length
 func (g *Generator) generateMixedSuffixWithLength(length int) string {
-	if length < 3 {
-		length = 3
-	} else if length > 8 {
-		length = 8
-	}
+	length = clampSuffixLength(SuffixMixed, length)
 
 	chars := "abcdefghijklmnopqrstuvwxyz0123456789"
+	if !g.config.IncludeNumbers {
+		chars = chars[:26]
+	}
 	var suffix strings.Builder
 
 	for i := 0; i < length; i++ {
@@ -355,10 +425,12 @@ func DefaultConfig() Config {
 	return Config{
 		Category:       "all",
 		SuffixType:     SuffixMixed,
-		SuffixLength:   4,
+		SuffixLength:   DefaultSuffixLength,
 		IncludeNumbers: true,
 		UseEmojis:      false,
 		Count:          1,
 		Seed:           0,
+		MaxAttempts:    defaultMaxAttempts,
+		Sampling:       SamplingUniformCategory,
 	}
 }
@@ -0,0 +1,324 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TokenKind identifies a placeholder inside a name layout
+type TokenKind string
+
+const (
+	TokenLiteral  TokenKind = "literal"
+	TokenFrontend TokenKind = "frontend"
+	TokenBackend  TokenKind = "backend"
+	TokenCategory TokenKind = "category"
+	TokenWord     TokenKind = "word"
+	TokenSuffix   TokenKind = "suffix"
+	TokenDate     TokenKind = "date"
+)
+
+const (
+	// DefaultLayout is the layout used by the generate command, giving names such as pizza-a1b2
+	DefaultLayout = "{word}{suffix}"
+
+	// DefaultEnhancedLayout is the layout used by interactive mode, giving names such as rct-node-pizza-a1b2
+	DefaultEnhancedLayout = "{frontend}-{backend}-{word}{suffix}"
+
+	// defaultDateFormat is the Go time layout used by a bare {date} token
+	defaultDateFormat = "20060102"
+)
+
+// LayoutToken is a single parsed element of a layout
+type LayoutToken struct {
+	Kind    TokenKind
+	Text    string   // literal text for TokenLiteral
+	Options []string // per-token options, e.g. "upper" or suffix type and length
+}
+
+// Layout is a parsed naming convention such as "{frontend}-{backend}-{word}{suffix}".
+// Literal text between tokens is copied as-is, so it doubles as the separator.
+// The {suffix} token carries its own leading hyphen, as generated suffixes always have.
+type Layout struct {
+	source string
+	tokens []LayoutToken
+}
+
+// LayoutError describes why a layout string could not be parsed
+type LayoutError struct {
+	Layout   string
+	Position int
+	Message  string
+}
+
+func (e *LayoutError) Error() string {
+	return fmt.Sprintf("invalid layout %q at position %d: %s", e.Layout, e.Position, e.Message)
+}
+
+// ParseLayout parses and validates a layout string.
+//
+// Supported tokens:
+//
+//	{frontend}, {backend}, {category}, {word}  optional case option: upper, lower, title
+//	{suffix}                                   suffix using the selected type and length
+//	{suffix:TYPE} / {suffix:TYPE:LENGTH}       suffix with its own type and length
+//	{date} / {date:FORMAT}                     current date, FORMAT is a Go time layout
+func ParseLayout(layout string) (*Layout, error) {
+	if strings.TrimSpace(layout) == "" {
+		return nil, &LayoutError{Layout: layout, Position: 0, Message: "layout is empty"}
+	}
+
+	l := &Layout{source: layout}
+	var literal strings.Builder
+
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			l.tokens = append(l.tokens, LayoutToken{Kind: TokenLiteral, Text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(layout); i++ {
+		switch layout[i] {
+		case '{':
+			end := strings.IndexByte(layout[i:], '}')
+			if end < 0 {
+				return nil, &LayoutError{Layout: layout, Position: i, Message: "unclosed '{'"}
+			}
+			body := layout[i+1 : i+end]
+			if strings.ContainsRune(body, '{') {
+				return nil, &LayoutError{Layout: layout, Position: i, Message: "nested '{' inside token"}
+			}
+
+			token, err := parseLayoutToken(body)
+			if err != nil {
+				return nil, &LayoutError{Layout: layout, Position: i, Message: err.Error()}
+			}
+
+			flushLiteral()
+			l.tokens = append(l.tokens, token)
+			i += end
+		case '}':
+			return nil, &LayoutError{Layout: layout, Position: i, Message: "unexpected '}'"}
+		default:
+			literal.WriteByte(layout[i])
+		}
+	}
+	flushLiteral()
+
+	if !l.Uses(TokenWord) && !l.Uses(TokenSuffix) && !l.Uses(TokenDate) {
+		return nil, &LayoutError{Layout: layout, Position: 0, Message: "layout must contain at least one of {word}, {suffix} or {date}"}
+	}
+
+	return l, nil
+}
+
+// MustParseLayout is like ParseLayout but panics on error. It is intended for built-in layouts.
+func MustParseLayout(layout string) *Layout {
+	l, err := ParseLayout(layout)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+func parseLayoutToken(body string) (LayoutToken, error) {
+	if body == "" {
+		return LayoutToken{}, fmt.Errorf("empty token '{}'")
+	}
+
+	name, rest, hasOptions := strings.Cut(body, ":")
+	kind := TokenKind(strings.ToLower(strings.TrimSpace(name)))
+
+	switch kind {
+	case TokenFrontend, TokenBackend, TokenCategory, TokenWord:
+		if !hasOptions {
+			return LayoutToken{Kind: kind}, nil
+		}
+		option := strings.ToLower(rest)
+		switch option {
+		case "upper", "lower", "title":
+			return LayoutToken{Kind: kind, Options: []string{option}}, nil
+		}
+		return LayoutToken{}, fmt.Errorf("unknown option %q for {%s} (use upper, lower or title)", rest, kind)
+
+	case TokenSuffix:
+		if !hasOptions {
+			return LayoutToken{Kind: kind}, nil
+		}
+		options := strings.Split(rest, ":")
+		if len(options) > 2 {
+			return LayoutToken{}, fmt.Errorf("{suffix} takes at most a type and a length, got %q", rest)
+		}
+		suffixType, err := ParseSuffixType(options[0])
+		if err != nil {
+			return LayoutToken{}, err
+		}
+		if len(options) == 2 {
+			length, err := strconv.Atoi(options[1])
+			if err != nil {
+				return LayoutToken{}, fmt.Errorf("invalid suffix length %q", options[1])
+			}
+			spec, _ := LookupSuffixSpec(suffixType)
+			if length < spec.MinLength || length > spec.MaxLength {
+				return LayoutToken{}, fmt.Errorf("%s suffix length must be between %d and %d, got %d", suffixType, spec.MinLength, spec.MaxLength, length)
+			}
+		}
+		return LayoutToken{Kind: kind, Options: options}, nil
+
+	case TokenDate:
+		// Everything after the first colon is the format, since Go time layouts may contain colons
+		if !hasOptions {
+			return LayoutToken{Kind: kind}, nil
+		}
+		if rest == "" {
+			return LayoutToken{}, fmt.Errorf("empty date format")
+		}
+		return LayoutToken{Kind: kind, Options: []string{rest}}, nil
+	}
+
+	return LayoutToken{}, fmt.Errorf("unknown token {%s} (known tokens: frontend, backend, category, word, suffix, date)", name)
+}
+
+// String returns the original layout string
+func (l *Layout) String() string {
+	return l.source
+}
+
+// Tokens returns the parsed tokens of the layout
+func (l *Layout) Tokens() []LayoutToken {
+	return l.tokens
+}
+
+// Uses reports whether the layout contains a token of the given kind
+func (l *Layout) Uses(kind TokenKind) bool {
+	for _, t := range l.tokens {
+		if t.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Selection holds the choices a layout is rendered with
+type Selection struct {
+	Frontend string
+	Backend  string
+	Category string
+	Word     string // fixed word; when empty a word is picked from Category
+}
+
+// render builds a single name from the layout and selection, keeping it
+// within MaxLength when one is configured. It also returns the selection with
+// the picked category and word filled in.
+func (g *Generator) render(layout *Layout, sel Selection, suffixType SuffixType, length int, now time.Time) (string, Selection, error) {
+	if layout.Uses(TokenFrontend) && sel.Frontend == "" {
+		return "", sel, fmt.Errorf("layout %q uses {frontend} but no frontend was selected", layout)
+	}
+	if layout.Uses(TokenBackend) && sel.Backend == "" {
+		return "", sel, fmt.Errorf("layout %q uses {backend} but no backend was selected", layout)
+	}
+
+	plan, err := g.planBudget(layout, sel, suffixType, length, now)
+	if err != nil {
+		return "", sel, err
+	}
+
+	category := sel.Category
+	word := sel.Word
+	if word == "" && (layout.Uses(TokenWord) || layout.Uses(TokenCategory)) {
+		g.wordLimit = plan.wordLimit
+		category, word = g.pickWord(category)
+		g.wordLimit = 0
+	}
+
+	values := func(t LayoutToken) string {
+		switch t.Kind {
+		case TokenLiteral:
+			return t.Text
+		case TokenFrontend:
+			return applyCaseOption(plan.frontend, t.Options)
+		case TokenBackend:
+			return applyCaseOption(plan.backend, t.Options)
+		case TokenCategory:
+			return applyCaseOption(category, t.Options)
+		case TokenWord:
+			return applyCaseOption(word, t.Options)
+		case TokenDate:
+			return now.Format(dateFormat(t))
+		}
+		return ""
+	}
+
+	used := 0
+	for _, t := range layout.tokens {
+		used += len(values(t))
+	}
+	suffixLengths := g.fitSuffixes(plan, used)
+
+	var name strings.Builder
+	suffixes := 0
+	for _, t := range layout.tokens {
+		if t.Kind != TokenSuffix {
+			name.WriteString(values(t))
+			continue
+		}
+
+		st, _ := suffixTokenConfig(t, suffixType, length)
+		sl := suffixLengths[suffixes] - 1 // the budget counts the leading "-"
+		suffixes++
+		if st == SuffixSequence {
+			name.WriteString(g.generateSequenceSuffix(name.String(), sl))
+			continue
+		}
+		suffix, err := g.filteredSuffix(st, sl)
+		if err != nil {
+			return "", sel, err
+		}
+		name.WriteString(suffix)
+	}
+
+	picked := sel
+	picked.Category, picked.Word = category, word
+	return name.String(), picked, nil
+}
+
+// dateFormat returns the Go time layout of a {date} token
+func dateFormat(t LayoutToken) string {
+	if len(t.Options) > 0 {
+		return t.Options[0]
+	}
+	return defaultDateFormat
+}
+
+// suffixTokenConfig returns the suffix type and length for a {suffix} token,
+// falling back to the selected ones when the token has no options
+func suffixTokenConfig(t LayoutToken, suffixType SuffixType, length int) (SuffixType, int) {
+	if len(t.Options) > 0 {
+		suffixType, _ = ParseSuffixType(t.Options[0])
+	}
+	if len(t.Options) > 1 {
+		length, _ = strconv.Atoi(t.Options[1])
+	}
+	return suffixType, length
+}
+
+func applyCaseOption(value string, options []string) string {
+	if len(options) == 0 {
+		return value
+	}
+	switch options[0] {
+	case "upper":
+		return strings.ToUpper(value)
+	case "lower":
+		return strings.ToLower(value)
+	case "title":
+		if value == "" {
+			return value
+		}
+		return strings.ToUpper(value[:1]) + value[1:]
+	}
+	return value
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   []LayoutToken
+	}{
+		{"{word}{suffix}", []LayoutToken{{Kind: TokenWord}, {Kind: TokenSuffix}}},
+		{"{frontend}-{backend}-{word}{suffix}", []LayoutToken{
+			{Kind: TokenFrontend},
+			{Kind: TokenLiteral, Text: "-"},
+			{Kind: TokenBackend},
+			{Kind: TokenLiteral, Text: "-"},
+			{Kind: TokenWord},
+			{Kind: TokenSuffix},
+		}},
+		{"{WORD:Upper}", []LayoutToken{{Kind: TokenWord, Options: []string{"upper"}}}},
+		{"{suffix:hex:6}", []LayoutToken{{Kind: TokenSuffix, Options: []string{"hex", "6"}}}},
+		{"{suffix:sequence}", []LayoutToken{{Kind: TokenSuffix, Options: []string{"sequence"}}}},
+		{"{date:15:04}", []LayoutToken{{Kind: TokenDate, Options: []string{"15:04"}}}},
+		{"app_{date}", []LayoutToken{{Kind: TokenLiteral, Text: "app_"}, {Kind: TokenDate}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			l, err := ParseLayout(tt.layout)
+			if err != nil {
+				t.Fatalf("ParseLayout(%q): %v", tt.layout, err)
+			}
+			if !reflect.DeepEqual(l.Tokens(), tt.want) {
+				t.Errorf("ParseLayout(%q) tokens = %+v, want %+v", tt.layout, l.Tokens(), tt.want)
+			}
+			if l.String() != tt.layout {
+				t.Errorf("String() = %q, want %q", l.String(), tt.layout)
+			}
+		})
+	}
+}
+
+func TestParseLayoutErrors(t *testing.T) {
+	tests := []struct {
+		layout   string
+		position int
+		message  string
+	}{
+		{"", 0, "layout is empty"},
+		{"   ", 0, "layout is empty"},
+		{"{word", 0, "unclosed '{'"},
+		{"x-{word}}", 8, "unexpected '}'"},
+		{"a{b{word}}", 1, "nested '{'"},
+		{"{word}-{}", 7, "empty token"},
+		{"{noun}", 0, "unknown token {noun}"},
+		{"{word:shout}", 0, "unknown option"},
+		{"{suffix:hex:6:x}", 0, "at most a type and a length"},
+		{"{suffix:nope}", 0, "nope"},
+		{"{suffix:hex:x}", 0, "invalid suffix length"},
+		{"{suffix:uuid7:8}", 0, "between 20 and 32"},
+		{"{date:}", 0, "empty date format"},
+		{"{frontend}-{backend}", 0, "at least one of {word}, {suffix} or {date}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			_, err := ParseLayout(tt.layout)
+			var layoutErr *LayoutError
+			if !errors.As(err, &layoutErr) {
+				t.Fatalf("ParseLayout(%q) error = %v, want a *LayoutError", tt.layout, err)
+			}
+			if layoutErr.Position != tt.position {
+				t.Errorf("position = %d, want %d", layoutErr.Position, tt.position)
+			}
+			if !strings.Contains(layoutErr.Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", layoutErr.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestRenderLayout(t *testing.T) {
+	sel := Selection{Frontend: "rct", Backend: "node", Category: "food"}
+
+	tests := []struct {
+		layout string
+		sel    Selection
+		want   string // regular expression the whole name must match
+	}{
+		{DefaultLayout, sel, `pizza-[a-z0-9]{4}`},
+		{DefaultEnhancedLayout, sel, `rct-node-pizza-[a-z0-9]{4}`},
+		{"{frontend}_{backend}_{word}", sel, `rct_node_pizza`},
+		{"{category}-{word}", sel, `food-pizza`},
+		{"{category}-{word}", Selection{}, `food-pizza`},
+		{"{word:title}{frontend:upper}", sel, `PizzaRCT`},
+		{"{word}{suffix:hex:6}", sel, `pizza-[0-9a-f]{6}`},
+		{"{word}{suffix:numeric}", sel, `pizza-[1-9][0-9]{3}`},
+		{"{word}{suffix:alpha:5}{suffix}", sel, `pizza-[a-z]{5}-[a-z0-9]{4}`},
+		{"{word}{suffix:sequence:3}", sel, `pizza-001`},
+		{"{date}-{word}", sel, `20250102-pizza`},
+		{"{date:2006-01}/{word}", sel, `2025-01/pizza`},
+		{"{word}-{date:15.04}", sel, `pizza-15\.04`},
+		{"{word}", Selection{Word: "taco"}, `taco`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			g := NewGenerator(Config{
+				Seed:           3,
+				Categories:     map[string][]string{"food": {"pizza"}},
+				IncludeNumbers: true,
+				Now:            time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+				SkipDiskCheck:  true,
+			})
+			name, _, err := g.render(MustParseLayout(tt.layout), tt.sel, SuffixMixed, 4, g.now())
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if !regexp.MustCompile(`^` + tt.want + `$`).MatchString(name) {
+				t.Errorf("render(%q) = %q, want a match for %s", tt.layout, name, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMissingCodes(t *testing.T) {
+	g := NewGenerator(Config{Categories: map[string][]string{"food": {"pizza"}}})
+	for _, layout := range []string{"{frontend}-{word}", "{backend}-{word}"} {
+		if _, _, err := g.render(MustParseLayout(layout), Selection{Category: "food"}, SuffixMixed, 4, g.now()); err == nil {
+			t.Errorf("render(%q) without codes succeeded, want an error", layout)
+		}
+	}
+}
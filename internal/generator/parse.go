@@ -0,0 +1,419 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxPhraseWords bounds how many known words a {word} token is split into
+const maxPhraseWords = 3
+
+// ParsedName holds the components a name was rendered from
+type ParsedName struct {
+	Name       string
+	Layout     string
+	Emoji      string
+	Frontend   string
+	Backend    string
+	Word       string
+	Category   string   // category of the word, or the {category} token; "" for unknown words
+	Categories []string // every category the word appears in
+	Date       string
+	Suffixes   []ParsedSuffix
+}
+
+// ParsedSuffix is a suffix found in a name, without its separator
+type ParsedSuffix struct {
+	Text       string
+	Type       SuffixType   // most likely type
+	Candidates []SuffixType // every type that can produce Text
+}
+
+// suffixPreference orders suffix types from the most to the least specific.
+// The default mixed type goes before hex and base32, which it often looks like.
+var suffixPreference = []SuffixType{
+	SuffixDate, SuffixDateTime, SuffixUUIDv7, SuffixULID, SuffixTimestamp, SuffixNumeric,
+	SuffixSequence, SuffixPronounceable, SuffixAlpha, SuffixMixed, SuffixHex, SuffixBase32,
+}
+
+var pronounceablePattern = regexp.MustCompile(`^(?:[` + consonants + `][` + vowels + `])*[` + consonants + `]?[0-9]*$`)
+
+// ParseName splits a name back into the components of layout. Words are
+// matched against the categories, so words containing hyphens such as
+// "cherry-pick" are recovered whole; codes and words missing from the config
+// are accepted as single parts. Separators and case styles are ignored.
+// When a suffix fits several types, hint is preferred over the most specific type.
+func (g *Generator) ParseName(layout *Layout, name string, hint SuffixType) (ParsedName, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	parts, emoji := splitName(name)
+	p := &nameParser{
+		g:      g,
+		tokens: layout.tokens,
+		text:   strings.Join(parts, "-"),
+		joined: !strings.ContainsFunc(name, isNameSeparator),
+		hint:   hint,
+		words:  g.knownWords(),
+	}
+	p.result.Emoji = emoji
+	p.result.Name = name
+	p.result.Layout = layout.String()
+
+	if !p.match(0, 0) {
+		return ParsedName{}, fmt.Errorf("name %q does not match layout %s", name, layout)
+	}
+	return p.result, nil
+}
+
+// splitName lowercases a name and splits it into parts on separators and
+// camel case boundaries. Leading symbols such as an emoji are returned separately.
+func splitName(name string) ([]string, string) {
+	emoji := ""
+	trimmed := strings.TrimLeftFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if prefix := strings.TrimRightFunc(name[:len(name)-len(trimmed)], isNameSeparator); prefix != "" {
+		emoji = prefix
+	}
+
+	var parts []string
+	for _, field := range strings.FieldsFunc(trimmed, isNameSeparator) {
+		parts = append(parts, splitCamel(field)...)
+	}
+	for i, part := range parts {
+		parts[i] = strings.ToLower(part)
+	}
+	return parts, emoji
+}
+
+// splitCamel splits "pizzaNightA1b2" into "pizza", "Night" and "A1b2".
+// Parts without lower case letters, such as "A1B2", are kept whole.
+func splitCamel(s string) []string {
+	if strings.ToUpper(s) == s || strings.ToLower(s) == s {
+		return []string{s}
+	}
+
+	var parts []string
+	runes := []rune(s)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev := runes[i-1]
+		if unicode.IsUpper(runes[i]) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
+
+// nameParser matches a normalized name against layout tokens, backtracking
+// until the whole name is consumed
+type nameParser struct {
+	g      *Generator
+	tokens []LayoutToken
+	text   string // lowercased name with "-" between its parts
+	joined bool   // the name has no separators, so tokens may start inside a part
+	hint   SuffixType
+	words  []string // known words in normalized form, longest first
+	result ParsedName
+}
+
+// normalize lowercases s and puts "-" between its parts
+func normalize(s string) string {
+	parts, _ := splitName(s)
+	return strings.Join(parts, "-")
+}
+
+func (p *nameParser) match(ti, pos int) bool {
+	// Tokens start after the separator in front of them
+	if pos < len(p.text) && p.text[pos] == '-' {
+		pos++
+	}
+	if ti == len(p.tokens) {
+		return pos == len(p.text)
+	}
+
+	t := p.tokens[ti]
+	afterLiteral := ti > 0 && p.tokens[ti-1].Kind == TokenLiteral
+	if t.Kind != TokenLiteral && !afterLiteral && !p.joined && pos > 0 && p.text[pos-1] != '-' {
+		return false
+	}
+
+	saved := p.result
+	try := func(end int, apply func()) bool {
+		apply()
+		if p.match(ti+1, end) {
+			return true
+		}
+		p.result = saved
+		return false
+	}
+
+	switch t.Kind {
+	case TokenLiteral:
+		lit := normalize(t.Text)
+		if lit == "" {
+			return p.match(ti+1, pos)
+		}
+		end, ok := p.at(pos, lit)
+		return ok && p.match(ti+1, end)
+
+	case TokenFrontend, TokenBackend:
+		codes := p.g.config.FrontendCodes
+		if t.Kind == TokenBackend {
+			codes = p.g.config.BackendCodes
+		}
+		set := func(code string) func() {
+			return func() {
+				if t.Kind == TokenFrontend {
+					p.result.Frontend = code
+				} else {
+					p.result.Backend = code
+				}
+			}
+		}
+		for _, code := range codes {
+			if end, ok := p.at(pos, normalize(code)); ok && try(end, set(strings.ToLower(code))) {
+				return true
+			}
+		}
+		// Unknown or abbreviated codes
+		end := p.partEnd(pos)
+		return end > pos && try(end, set(p.text[pos:end]))
+
+	case TokenCategory:
+		for _, cat := range sortedKeys(p.g.config.Categories) {
+			if end, ok := p.at(pos, normalize(cat)); ok && try(end, func() { p.result.Category = cat }) {
+				return true
+			}
+		}
+		return false
+
+	case TokenWord:
+		for _, phrase := range p.phrases(pos) {
+			last := phrase.words[len(phrase.words)-1]
+			if try(phrase.end, func() {
+				p.result.Word = strings.Join(phrase.words, "-")
+				p.result.Categories = p.g.categoriesOf(last)
+				if len(p.result.Categories) > 0 {
+					p.result.Category = p.result.Categories[0]
+				}
+			}) {
+				return true
+			}
+		}
+		// A custom word: take as few parts as the rest of the layout allows
+		for end := p.partEnd(pos); end > pos; end = p.partEnd(end + 1) {
+			if try(end, func() { p.result.Word = p.text[pos:end] }) {
+				return true
+			}
+			if end == len(p.text) {
+				break
+			}
+		}
+		return false
+
+	case TokenDate:
+		// Keep the case of the format, which tells month names from numbers
+		format := strings.FieldsFunc(dateFormat(t), isNameSeparator)
+		end := p.partEnd(pos)
+		for i := 1; i < len(format) && end < len(p.text); i++ {
+			end = p.partEnd(end + 1)
+		}
+		value := p.text[pos:end]
+		if _, err := time.Parse(strings.Join(format, "-"), value); err != nil {
+			return false
+		}
+		return try(end, func() { p.result.Date = value })
+
+	case TokenSuffix:
+		// A datetime suffix spans two parts
+		end := p.partEnd(pos)
+		for i := 0; i < 2 && end > pos; i++ {
+			suffix, ok := p.suffix(t, p.text[pos:end])
+			if ok && try(end, func() { p.result.Suffixes = append(p.result.Suffixes, suffix) }) {
+				return true
+			}
+			if end == len(p.text) {
+				break
+			}
+			end = p.partEnd(end + 1)
+		}
+		return false
+	}
+
+	return false
+}
+
+// at reports where s ends when the name continues with s at pos
+func (p *nameParser) at(pos int, s string) (int, bool) {
+	if s == "" || !strings.HasPrefix(p.text[pos:], s) {
+		return 0, false
+	}
+	return pos + len(s), true
+}
+
+// partEnd returns the end of the part containing pos
+func (p *nameParser) partEnd(pos int) int {
+	if i := strings.IndexByte(p.text[pos:], '-'); i >= 0 {
+		return pos + i
+	}
+	return len(p.text)
+}
+
+// phrase is a sequence of known words spelled by the name up to end
+type phrase struct {
+	words []string
+	end   int
+}
+
+// phrases returns the sequences of known words the name spells from pos,
+// fewest and longest words first
+func (p *nameParser) phrases(pos int) []phrase {
+	var result []phrase
+	var walk func(at int, words []string)
+	walk = func(at int, words []string) {
+		if len(words) > 0 {
+			result = append(result, phrase{words: append([]string(nil), words...), end: at})
+		}
+		if len(words) == maxPhraseWords {
+			return
+		}
+		if len(words) > 0 {
+			if at < len(p.text) && p.text[at] == '-' {
+				at++
+			} else if !p.joined {
+				return
+			}
+		}
+		for _, w := range p.words {
+			if end, ok := p.at(at, w); ok {
+				walk(end, append(words, w))
+			}
+		}
+	}
+	walk(pos, nil)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if len(result[i].words) != len(result[j].words) {
+			return len(result[i].words) < len(result[j].words)
+		}
+		return result[i].end > result[j].end
+	})
+	return result
+}
+
+// suffix identifies the suffix types that can produce text for a {suffix} token
+func (p *nameParser) suffix(t LayoutToken, text string) (ParsedSuffix, bool) {
+	types := suffixPreference
+	if len(t.Options) > 0 {
+		fixed, _ := ParseSuffixType(t.Options[0])
+		types = []SuffixType{fixed}
+	}
+
+	suffix := ParsedSuffix{Text: text}
+	for _, st := range types {
+		if matchesSuffix(st, text) {
+			suffix.Candidates = append(suffix.Candidates, st)
+		}
+	}
+	if len(suffix.Candidates) == 0 {
+		return suffix, false
+	}
+
+	suffix.Type = suffix.Candidates[0]
+	for _, st := range suffix.Candidates {
+		if st == p.hint {
+			suffix.Type = st
+		}
+	}
+	return suffix, true
+}
+
+// matchesSuffix reports whether a suffix type can produce text
+func matchesSuffix(st SuffixType, text string) bool {
+	spec, ok := LookupSuffixSpec(st)
+	if !ok || len(text) < spec.MinLength || len(text) > spec.MaxLength {
+		return false
+	}
+
+	only := func(chars string) bool {
+		for _, r := range text {
+			if !strings.ContainsRune(chars, r) {
+				return false
+			}
+		}
+		return true
+	}
+	const digits = "0123456789"
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	const hexChars = "0123456789abcdef"
+
+	switch st {
+	case SuffixAlpha:
+		return only(letters)
+	case SuffixNumeric:
+		return only(digits) && text[0] != '0'
+	case SuffixMixed:
+		return only(letters + digits)
+	case SuffixTimestamp, SuffixSequence:
+		return only(digits)
+	case SuffixULID, SuffixBase32:
+		return only(crockfordAlphabet)
+	case SuffixUUIDv7:
+		return only(hexChars) && text[12] == '7'
+	case SuffixHex:
+		return only(hexChars)
+	case SuffixDate:
+		_, err := time.Parse("20060102", text)
+		return err == nil
+	case SuffixDateTime:
+		_, err := time.Parse("060102-1504", text)
+		return err == nil
+	case SuffixPronounceable:
+		letterCount := len(strings.TrimRight(text, digits))
+		return letterCount >= 2 && pronounceablePattern.MatchString(text)
+	}
+	return false
+}
+
+// knownWords returns every word of every category in normalized form, longest first
+func (g *Generator) knownWords() []string {
+	seen := make(map[string]struct{})
+	var words []string
+	for _, list := range g.config.Categories {
+		for _, w := range list {
+			w = normalize(w)
+			if _, ok := seen[w]; !ok {
+				seen[w] = struct{}{}
+				words = append(words, w)
+			}
+		}
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if len(words[i]) != len(words[j]) {
+			return len(words[i]) > len(words[j])
+		}
+		return words[i] < words[j]
+	})
+	return words
+}
+
+// categoriesOf returns the sorted categories containing word
+func (g *Generator) categoriesOf(word string) []string {
+	var categories []string
+	for _, cat := range sortedKeys(g.config.Categories) {
+		for _, w := range g.config.Categories[cat] {
+			if strings.EqualFold(w, word) {
+				categories = append(categories, cat)
+				break
+			}
+		}
+	}
+	return categories
+}
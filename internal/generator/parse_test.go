@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseName(t *testing.T) {
+	g := NewGenerator(Config{
+		Seed: 1,
+		Categories: map[string][]string{
+			"food":  {"pizza", "hot-dog"},
+			"moods": {"goofy"},
+		},
+		FrontendCodes: []string{"rct", "vue"},
+		BackendCodes:  []string{"node", "py"},
+	})
+
+	tests := []struct {
+		layout  string
+		name    string
+		hint    SuffixType
+		want    string // "frontend backend word category emoji suffix:type"
+		wantErr string
+	}{
+		{DefaultEnhancedLayout, "rct-node-pizza-a1b2", "", "rct node pizza food  a1b2:mixed", ""},
+		{DefaultEnhancedLayout, "RctNodePizzaA1b2", "", "rct node pizza food  a1b2:mixed", ""},
+		{DefaultEnhancedLayout, "rct_node_hot_dog_kqzv", "", "rct node hot-dog food  kqzv:alpha", ""},
+		{DefaultEnhancedLayout, "🍕-vue-py-pizza-0042", "", "vue py pizza food 🍕 0042:sequence", ""},
+		{DefaultEnhancedLayout, "vue-py-goofy-4821", SuffixHex, "vue py goofy moods  4821:hex", ""},
+		{DefaultLayout, "otter-20250102", "", "  otter   20250102:date", ""},
+		{DefaultLayout, "pizza-01j9x2k7qmab", "", "  pizza food  01j9x2k7qmab:ulid", ""},
+		{DefaultEnhancedLayout, "pizza", "", "", "does not match layout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := g.ParseName(MustParseLayout(tt.layout), tt.name, tt.hint)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseName(%q) error = %v, want %q", tt.name, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseName(%q): %v", tt.name, err)
+			}
+
+			var suffixes []string
+			for _, s := range parsed.Suffixes {
+				suffixes = append(suffixes, s.Text+":"+string(s.Type))
+			}
+			got := strings.Join([]string{parsed.Frontend, parsed.Backend, parsed.Word, parsed.Category, parsed.Emoji, strings.Join(suffixes, ",")}, " ")
+			if got != tt.want {
+				t.Errorf("ParseName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SamplingStrategy controls how a category and word are picked
+type SamplingStrategy string
+
+const (
+	// SamplingUniformCategory picks a category uniformly, then a word from it uniformly.
+	// Words of small categories are therefore more likely with "all".
+	SamplingUniformCategory SamplingStrategy = "uniform-category"
+
+	// SamplingUniformWord gives every word of the candidate categories the same chance
+	SamplingUniformWord SamplingStrategy = "uniform-word"
+
+	// SamplingWeighted picks a category by its weight, then a word by its weight.
+	// Categories and words without a weight count as 1.
+	SamplingWeighted SamplingStrategy = "weighted"
+)
+
+// ParseSamplingStrategy converts a user supplied strategy name into a SamplingStrategy
+func ParseSamplingStrategy(s string) (SamplingStrategy, error) {
+	switch SamplingStrategy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", SamplingUniformCategory:
+		return SamplingUniformCategory, nil
+	case SamplingUniformWord:
+		return SamplingUniformWord, nil
+	case SamplingWeighted:
+		return SamplingWeighted, nil
+	}
+	return "", fmt.Errorf("unknown sampling strategy %q (use uniform-category, uniform-word or weighted)", s)
+}
+
+// candidateCategories returns the categories a selection may pick words from.
+// Categories without words would only produce the "folder" fallback, so they are left out.
+func (g *Generator) candidateCategories(category string) []string {
+	names := g.categorySet(category).names
+	candidates := make([]string, 0, len(names))
+	for _, cat := range names {
+		if len(g.config.Categories[cat]) > 0 {
+			candidates = append(candidates, cat)
+		}
+	}
+	return candidates
+}
+
+// pickWord picks a category and a word from it according to the sampling strategy.
+// With a composition pattern the word is a phrase such as "goofy-penguin".
+func (g *Generator) pickWord(category string) (string, string) {
+	if g.config.Composition != nil {
+		return g.composeWord(category)
+	}
+	return g.pickWordFrom(g.candidateCategories(category))
+}
+
+// pickWordFrom picks a category out of candidates and a word from it,
+// re-rolling words that the blocklist rejects
+func (g *Generator) pickWordFrom(candidates []string) (string, string) {
+	for try := 0; try < maxBlockedRerolls; try++ {
+		category, word := g.sampleWord(candidates)
+		if !g.config.Blocklist.BlocksWord(word) {
+			return category, word
+		}
+		g.rejections.Words++
+	}
+
+	// Most words are blocked, so pick among the allowed ones directly
+	g.skipBlocked = true
+	defer func() { g.skipBlocked = false }()
+	return g.sampleWord(candidates)
+}
+
+// sampleWord picks a category and word according to the sampling strategy
+func (g *Generator) sampleWord(candidates []string) (string, string) {
+	if len(candidates) == 0 {
+		return "", "folder"
+	}
+	candidates = g.withAcceptedWords(candidates)
+
+	switch g.config.Sampling {
+	case SamplingUniformWord:
+		return g.pickUniformWord(candidates)
+	case SamplingWeighted:
+		return g.pickWeightedWord(candidates)
+	default:
+		category := candidates[0]
+		if len(candidates) > 1 {
+			category = candidates[g.rand.Intn(len(candidates))]
+		}
+		return category, g.selectWordFromCategory(category)
+	}
+}
+
+// withAcceptedWords drops the categories whose words were all rejected by
+// acceptWord, unless that would leave no candidates at all
+func (g *Generator) withAcceptedWords(candidates []string) []string {
+	kept := make([]string, 0, len(candidates))
+	for _, cat := range candidates {
+		if len(g.config.Categories[cat]) == 0 || len(g.words(cat)) > 0 {
+			kept = append(kept, cat)
+		}
+	}
+	if len(kept) == 0 {
+		return candidates
+	}
+	return kept
+}
+
+func (g *Generator) pickUniformWord(candidates []string) (string, string) {
+	total := 0
+	for _, cat := range candidates {
+		total += len(g.words(cat))
+	}
+	if total == 0 {
+		return candidates[0], "folder"
+	}
+
+	n := g.rand.Intn(total)
+	for _, cat := range candidates {
+		words := g.words(cat)
+		if n < len(words) {
+			return cat, words[n]
+		}
+		n -= len(words)
+	}
+
+	return candidates[0], "folder"
+}
+
+func (g *Generator) pickWeightedWord(candidates []string) (string, string) {
+	weights := make([]float64, len(candidates))
+	for i, cat := range candidates {
+		if len(g.words(cat)) == 0 {
+			// Empty categories would only ever yield the fallback word
+			continue
+		}
+		weights[i] = weightOrDefault(g.config.CategoryWeights[cat])
+	}
+
+	i := g.weightedIndex(weights)
+	if i < 0 {
+		return candidates[0], "folder"
+	}
+	cat := candidates[i]
+
+	words := g.words(cat)
+	wordWeights := make([]float64, len(words))
+	for j, w := range words {
+		wordWeights[j] = weightOrDefault(g.config.WordWeights[cat][w])
+	}
+
+	return cat, words[g.weightedIndex(wordWeights)]
+}
+
+// weightedIndex picks an index with probability proportional to its weight, or -1 if all weights are zero
+func (g *Generator) weightedIndex(weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		return -1
+	}
+
+	r := g.rand.Float64() * total
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		last = i
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+
+	// Floating point rounding can leave r just above the final weight
+	return last
+}
+
+// sortedKeys returns the category names in a stable order, so seeded runs are reproducible
+func sortedKeys(categories map[string][]string) []string {
+	keys := make([]string, 0, len(categories))
+	for k := range categories {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func weightOrDefault(weight float64) float64 {
+	if weight <= 0 {
+		return 1
+	}
+	return weight
+}
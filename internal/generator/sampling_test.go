@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSamplingStrategy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    SamplingStrategy
+		wantErr bool
+	}{
+		{"", SamplingUniformCategory, false},
+		{"uniform-category", SamplingUniformCategory, false},
+		{" Uniform-Word ", SamplingUniformWord, false},
+		{"weighted", SamplingWeighted, false},
+		{"random", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSamplingStrategy(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSamplingStrategy(%q) = %q, %v, want %q (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSamplingDistribution(t *testing.T) {
+	categories := map[string][]string{
+		"food":  {"pizza", "taco", "pie", "kale"},
+		"space": {"comet"},
+		"empty": {},
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   map[string]float64
+	}{
+		{
+			name:   "uniform category",
+			config: Config{Sampling: SamplingUniformCategory},
+			want:   map[string]float64{"pizza": 0.125, "taco": 0.125, "pie": 0.125, "kale": 0.125, "comet": 0.5},
+		},
+		{
+			name:   "uniform word",
+			config: Config{Sampling: SamplingUniformWord},
+			want:   map[string]float64{"pizza": 0.2, "taco": 0.2, "pie": 0.2, "kale": 0.2, "comet": 0.2},
+		},
+		{
+			name: "weighted",
+			config: Config{
+				Sampling:        SamplingWeighted,
+				CategoryWeights: map[string]float64{"food": 3},
+				WordWeights:     map[string]map[string]float64{"food": {"pizza": 5}},
+			},
+			want: map[string]float64{"pizza": 0.46875, "taco": 0.09375, "pie": 0.09375, "kale": 0.09375, "comet": 0.25},
+		},
+	}
+
+	const draws = 40000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Seed = 11
+			tt.config.Categories = categories
+			g := NewGenerator(tt.config)
+
+			counts := make(map[string]int)
+			for i := 0; i < draws; i++ {
+				_, word := g.pickWord("all")
+				counts[word]++
+			}
+
+			for word, p := range tt.want {
+				if got := float64(counts[word]) / draws; math.Abs(got-p) > 0.015 {
+					t.Errorf("%s picked %.3f of the time, want %.3f", word, got, p)
+				}
+			}
+			if len(counts) != len(tt.want) {
+				t.Errorf("picked %v, want only %d words", counts, len(tt.want))
+			}
+
+			// The stats use the same probabilities
+			for word, p := range g.wordProbabilities("all") {
+				if math.Abs(p-tt.want[word]) > 1e-12 {
+					t.Errorf("wordProbabilities()[%s] = %g, want %g", word, p, tt.want[word])
+				}
+			}
+		})
+	}
+}
+
+func TestSamplingSeeded(t *testing.T) {
+	pick := func() []string {
+		g := NewGenerator(Config{
+			Seed:       21,
+			Categories: map[string][]string{"food": {"pizza", "taco"}, "space": {"comet", "nova"}},
+			Sampling:   SamplingWeighted,
+		})
+		words := make([]string, 20)
+		for i := range words {
+			_, words[i] = g.pickWord("all")
+		}
+		return words
+	}
+
+	first, second := pick(), pick()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("the same seed picked %v and %v", first, second)
+		}
+	}
+}
+
+func TestWeightedIndex(t *testing.T) {
+	g := NewGenerator(Config{Seed: 1})
+
+	if i := g.weightedIndex([]float64{0, 0}); i != -1 {
+		t.Errorf("weightedIndex() with zero weights = %d, want -1", i)
+	}
+	for n := 0; n < 100; n++ {
+		if i := g.weightedIndex([]float64{0, 2, 0}); i != 1 {
+			t.Fatalf("weightedIndex() = %d, want the only positive weight 1", i)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aravindcm49/dir-init/internal/utils"
+)
+
+// generateSequenceSuffix returns the next number of the series that starts with
+// prefix, zero-padded to length. The series continues after the highest number
+// found in the target directory, so gaps left by deleted directories are not reused.
+func (g *Generator) generateSequenceSuffix(prefix string, length int) string {
+	last, ok := g.sequences[prefix]
+	if !ok {
+		last = g.sequenceStart(prefix)
+	}
+
+	next := last + 1
+	g.sequences[prefix] = next
+
+	return fmt.Sprintf("-%0*d", length, next)
+}
+
+// sequenceStart returns the highest number already used for prefix
+func (g *Generator) sequenceStart(prefix string) int {
+	if start, ok := g.config.SequenceStarts[prefix]; ok {
+		g.sequenceStarts[prefix] = start
+		return start
+	}
+
+	// Existing names carry the case style, so match them against the styled
+	// prefix, with or without the emoji decorate puts in front
+	head := strings.TrimSuffix(g.applyStyle(prefix+"-0"), "0")
+
+	highest := 0
+	if !g.config.SkipDiskCheck {
+		entries, _ := os.ReadDir(g.targetDirOrDefault())
+		for _, entry := range entries {
+			n, ok := sequenceNumber(entry.Name(), head)
+			if !ok {
+				n, ok = sequenceNumber(undecorated(entry.Name()), head)
+			}
+			if ok && n > highest {
+				highest = n
+			}
+		}
+	}
+
+	g.sequenceStarts[prefix] = highest
+	return highest
+}
+
+// sequenceNumber parses the number following head in name
+func sequenceNumber(name, head string) (int, bool) {
+	if len(name) <= len(head) || name[:len(head)] != head {
+		return 0, false
+	}
+
+	rest := name[len(head):]
+	end := 0
+	for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(rest[:end])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// SequenceStarts returns the highest existing number found for every sequence prefix,
+// which lets a replay continue the series from the same point
+func (g *Generator) SequenceStarts() map[string]int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	starts := make(map[string]int, len(g.sequenceStarts))
+	for prefix, n := range g.sequenceStarts {
+		starts[prefix] = n
+	}
+	return starts
+}
+
+// CreateError reports a directory that could not be created
+type CreateError struct {
+	Name string
+	Err  error
+}
+
+func (e *CreateError) Error() string {
+	return fmt.Sprintf("failed to create directory '%s': %v", e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *CreateError) Unwrap() error {
+	return e.Err
+}
+
+// CreateName generates a name and creates it as a directory in the target directory.
+// Creating the directory is the final check: if another process created the same
+// name first, a new name is generated, so concurrent runs never share a directory.
+func (g *Generator) CreateName(layout *Layout, sel Selection, suffixType SuffixType, length int) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	maxAttempts := g.config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		name, err := g.generateName(layout, sel, suffixType, length)
+		if err != nil {
+			return "", err
+		}
+
+		err = utils.CreateDirectory(g.targetPath(name))
+		if errors.Is(err, os.ErrExist) {
+			g.onDisk[name] = struct{}{}
+			continue
+		}
+		if err != nil {
+			return "", &CreateError{Name: name, Err: err}
+		}
+
+		return name, nil
+	}
+
+	return "", g.newExhaustedError(layout, sel, suffixType, length, maxAttempts)
+}
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSequenceContinuesOnDisk(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  []string
+		caseStyle CaseStyle
+		emojis    bool
+		starts    map[string]int
+		want      []string
+	}{
+		{
+			name: "empty directory",
+			want: []string{"pizza-0001", "pizza-0002"},
+		},
+		{
+			name:     "after the highest number",
+			existing: []string{"pizza-0003", "pizza-0007", "pizza-notes", "taco-0042"},
+			want:     []string{"pizza-0008", "pizza-0009"},
+		},
+		{
+			name:     "wider numbers",
+			existing: []string{"pizza-12345"},
+			want:     []string{"pizza-12346", "pizza-12347"},
+		},
+		{
+			name:      "case style",
+			existing:  []string{"pizza_0005", "pizza-0009"},
+			caseStyle: CaseSnake,
+			want:      []string{"pizza_0006", "pizza_0007"},
+		},
+		{
+			name:     "emoji-decorated names",
+			existing: []string{"🍕-pizza-0004", "pizza-0002"},
+			emojis:   true,
+			want:     []string{"🍕-pizza-0005", "🍕-pizza-0006"},
+		},
+		{
+			name:     "plain names with emojis on",
+			existing: []string{"pizza-0006"},
+			emojis:   true,
+			want:     []string{"🍕-pizza-0007", "🍕-pizza-0008"},
+		},
+		{
+			name:     "replayed start",
+			existing: []string{"pizza-0007"},
+			starts:   map[string]int{"pizza": 2},
+			want:     []string{"pizza-0003", "pizza-0004"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.existing {
+				if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			g := NewGenerator(Config{
+				Category:       "food",
+				Categories:     map[string][]string{"food": {"pizza"}},
+				SuffixType:     SuffixSequence,
+				SuffixLength:   4,
+				IncludeNumbers: true,
+				Count:          2,
+				Seed:           1,
+				TargetDir:      dir,
+				CaseStyle:      tt.caseStyle,
+				UseEmojis:      tt.emojis,
+				Emojis:         map[string]map[string]string{"food": {"pizza": "🍕"}},
+				SequenceStarts: tt.starts,
+			})
+			names, err := g.Generate()
+			if err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Generate() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestSequenceNumber(t *testing.T) {
+	tests := []struct {
+		name, head string
+		want       int
+		ok         bool
+	}{
+		{"pizza-0042", "pizza-", 42, true},
+		{"pizza-0042-old", "pizza-", 42, true},
+		{"pizza-", "pizza-", 0, false},
+		{"pizza-x1", "pizza-", 0, false},
+		{"taco-0001", "pizza-", 0, false},
+		{"pizzas-0001", "pizza-", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sequenceNumber(tt.name, tt.head)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("sequenceNumber(%q, %q) = %d, %v, want %d, %v", tt.name, tt.head, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package generator
+
+import "math"
+
+// SpaceStats describes how many distinct names a layout can produce for a category
+type SpaceStats struct {
+	Category     string
+	Words        uint64 // distinct words or composed phrases
+	Suffixes     uint64 // distinct suffixes, multiplied over every {suffix} token
+	Combinations uint64 // Words × Suffixes, saturating at math.MaxUint64
+
+	// Coincidence is the chance that two random names are the same: the sum of
+	// the squared name probabilities. Weights and the sampling strategy make
+	// some words likelier than others, so it is usually above 1/Combinations.
+	Coincidence float64
+}
+
+// SpaceStats counts the names layout can produce for category with the given suffix.
+// Frontend and backend codes are fixed for a run and do not add combinations.
+func (g *Generator) SpaceStats(layout *Layout, category string, suffixType SuffixType, length int) SpaceStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := SpaceStats{Category: category, Words: 1, Suffixes: 1}
+
+	if layout.Uses(TokenWord) {
+		if g.config.Composition != nil {
+			stats.Words = g.compositionSpace(category)
+		} else {
+			stats.Words = uint64(g.wordSpace(category))
+		}
+	}
+
+	for _, t := range layout.tokens {
+		if t.Kind != TokenSuffix {
+			continue
+		}
+		st, sl := suffixTokenConfig(t, suffixType, length)
+		stats.Suffixes = mulSaturating(stats.Suffixes, g.suffixSpace(st, sl))
+	}
+
+	stats.Combinations = g.nameSpace(layout, Selection{Category: category}, suffixType, length)
+
+	// suffixes are drawn uniformly, words by the sampling strategy
+	stats.Coincidence = 1
+	if layout.Uses(TokenWord) {
+		if g.config.Composition != nil {
+			stats.Coincidence = 1 / float64(max(stats.Words, 1))
+		} else {
+			stats.Coincidence = 0
+			for _, p := range g.wordProbabilities(category) {
+				stats.Coincidence += p * p
+			}
+		}
+	}
+	stats.Coincidence /= float64(max(stats.Suffixes, 1))
+	if stats.Combinations > 0 {
+		// names that the length budget or casing make equal are counted once
+		stats.Coincidence = max(stats.Coincidence, 1/float64(stats.Combinations))
+	}
+	return stats
+}
+
+// CollisionProbability returns the chance that n random names of the category
+// are not all distinct, using the birthday bound with the real probabilities
+// of the names
+func (s SpaceStats) CollisionProbability(n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	if s.Combinations == 0 || uint64(n) > s.Combinations {
+		return 1
+	}
+
+	pairs := float64(n) * float64(n-1) / 2
+	return -math.Expm1(-pairs * s.Coincidence)
+}
+
+// NamesForProbability returns how many random names of the category give a
+// collision chance of p
+func (s SpaceStats) NamesForProbability(p float64) float64 {
+	if p <= 0 || p >= 1 || s.Coincidence <= 0 {
+		return 0
+	}
+	return math.Sqrt(2 * math.Log(1/(1-p)) / s.Coincidence)
+}
+
+// wordProbabilities returns the chance of each word of a category under the
+// sampling strategy, as sampleWord picks them. Words in several candidate
+// categories add up.
+func (g *Generator) wordProbabilities(category string) map[string]float64 {
+	probs := make(map[string]float64)
+	candidates := g.candidateCategories(category)
+	if len(candidates) == 0 {
+		probs["folder"] = 1
+		return probs
+	}
+	candidates = g.withAcceptedWords(candidates)
+
+	switch g.config.Sampling {
+	case SamplingUniformWord:
+		total := 0
+		for _, cat := range candidates {
+			total += len(g.words(cat))
+		}
+		if total == 0 {
+			probs["folder"] = 1
+			return probs
+		}
+		for _, cat := range candidates {
+			for _, w := range g.words(cat) {
+				probs[w] += 1 / float64(total)
+			}
+		}
+	case SamplingWeighted:
+		weights := make([]float64, len(candidates))
+		total := 0.0
+		for i, cat := range candidates {
+			if len(g.words(cat)) > 0 {
+				weights[i] = weightOrDefault(g.config.CategoryWeights[cat])
+				total += weights[i]
+			}
+		}
+		if total <= 0 {
+			probs["folder"] = 1
+			return probs
+		}
+		for i, cat := range candidates {
+			if weights[i] == 0 {
+				continue
+			}
+			words := g.words(cat)
+			wordTotal := 0.0
+			for _, w := range words {
+				wordTotal += weightOrDefault(g.config.WordWeights[cat][w])
+			}
+			for _, w := range words {
+				probs[w] += weights[i] / total * weightOrDefault(g.config.WordWeights[cat][w]) / wordTotal
+			}
+		}
+	default:
+		for _, cat := range candidates {
+			words := g.words(cat)
+			if len(words) == 0 {
+				probs["folder"] += 1 / float64(len(candidates))
+				continue
+			}
+			for _, w := range words {
+				probs[w] += 1 / float64(len(candidates)) / float64(len(words))
+			}
+		}
+	}
+	return probs
+}
+
+// CollisionProbability returns the birthday bound for the chance that n names
+// drawn at random from space combinations are not all distinct. It assumes
+// every combination is equally likely; SpaceStats.CollisionProbability uses
+// the real probabilities of the names.
+func CollisionProbability(n int, space uint64) float64 {
+	if n < 2 {
+		return 0
+	}
+	if space == 0 || uint64(n) > space {
+		return 1
+	}
+
+	pairs := float64(n) * float64(n-1) / 2
+	return -math.Expm1(-pairs / float64(space))
+}
+
+// NamesForProbability returns how many random names give a collision chance of
+// p when every one of space combinations is equally likely
+func NamesForProbability(p float64, space uint64) float64 {
+	if p <= 0 || p >= 1 {
+		return 0
+	}
+	return math.Sqrt(2 * float64(space) * math.Log(1/(1-p)))
+}
@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpaceStats(t *testing.T) {
+	categories := map[string][]string{
+		"food":  {"pizza", "taco"},
+		"space": {"comet"},
+	}
+
+	tests := []struct {
+		name         string
+		config       Config
+		layout       string
+		category     string
+		suffixType   SuffixType
+		length       int
+		words        uint64
+		suffixes     uint64
+		combinations uint64
+		coincidence  float64 // chance that two names are the same
+	}{
+		{
+			name:         "one category",
+			config:       Config{Categories: categories},
+			layout:       DefaultLayout,
+			category:     "food",
+			suffixType:   SuffixHex,
+			length:       2,
+			words:        2,
+			suffixes:     256,
+			combinations: 512,
+			coincidence:  1.0 / 512,
+		},
+		{
+			// comet is picked half of the time, pizza and taco a quarter each
+			name:         "uniform category makes small categories likelier",
+			config:       Config{Categories: categories},
+			layout:       DefaultLayout,
+			category:     "all",
+			suffixType:   SuffixHex,
+			length:       2,
+			words:        3,
+			suffixes:     256,
+			combinations: 768,
+			coincidence:  (0.25*0.25 + 0.25*0.25 + 0.5*0.5) / 256,
+		},
+		{
+			name:         "uniform word",
+			config:       Config{Categories: categories, Sampling: SamplingUniformWord},
+			layout:       DefaultLayout,
+			category:     "all",
+			suffixType:   SuffixHex,
+			length:       2,
+			words:        3,
+			suffixes:     256,
+			combinations: 768,
+			coincidence:  1.0 / 768,
+		},
+		{
+			name: "weighted words",
+			config: Config{
+				Categories:  categories,
+				Sampling:    SamplingWeighted,
+				WordWeights: map[string]map[string]float64{"food": {"pizza": 3}},
+			},
+			layout:       DefaultLayout,
+			category:     "food",
+			suffixType:   SuffixHex,
+			length:       2,
+			words:        2,
+			suffixes:     256,
+			combinations: 512,
+			coincidence:  (0.75*0.75 + 0.25*0.25) / 256,
+		},
+		{
+			name:         "two suffixes",
+			config:       Config{Categories: categories},
+			layout:       "{word}-{suffix:hex:2}{suffix:numeric:2}", // numeric suffixes do not start with 0
+			category:     "space",
+			suffixType:   SuffixMixed,
+			length:       4,
+			words:        1,
+			suffixes:     23040,
+			combinations: 23040,
+			coincidence:  1.0 / 23040,
+		},
+		{
+			name:         "no word",
+			config:       Config{Categories: categories},
+			layout:       "{suffix}",
+			category:     "all",
+			suffixType:   SuffixHex,
+			length:       3,
+			words:        1,
+			suffixes:     4096,
+			combinations: 4096,
+			coincidence:  1.0 / 4096,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(tt.config)
+			s := g.SpaceStats(MustParseLayout(tt.layout), tt.category, tt.suffixType, tt.length)
+			if s.Words != tt.words || s.Suffixes != tt.suffixes || s.Combinations != tt.combinations {
+				t.Errorf("SpaceStats() = %d words, %d suffixes, %d combinations, want %d, %d, %d",
+					s.Words, s.Suffixes, s.Combinations, tt.words, tt.suffixes, tt.combinations)
+			}
+			if math.Abs(s.Coincidence-tt.coincidence) > 1e-12 {
+				t.Errorf("Coincidence = %g, want %g", s.Coincidence, tt.coincidence)
+			}
+		})
+	}
+}
+
+func TestCollisionProbability(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats SpaceStats
+		n     int
+		want  float64
+	}{
+		{"one name", SpaceStats{Combinations: 100, Coincidence: 0.01}, 1, 0},
+		{"more names than combinations", SpaceStats{Combinations: 100, Coincidence: 0.01}, 101, 1},
+		{"no combinations", SpaceStats{}, 2, 1},
+		{"two names", SpaceStats{Combinations: 100, Coincidence: 0.01}, 2, -math.Expm1(-0.01)},
+		{"uniform", SpaceStats{Combinations: 1 << 20, Coincidence: 1.0 / (1 << 20)}, 1000, CollisionProbability(1000, 1<<20)},
+		// 45 pairs, each the same name with chance 1/10
+		{"uneven", SpaceStats{Combinations: 100, Coincidence: 0.1}, 10, 1 - math.Exp(-4.5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.CollisionProbability(tt.n); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("CollisionProbability(%d) = %g, want %g", tt.n, got, tt.want)
+			}
+		})
+	}
+
+	// with every name equally likely, the stats agree with the uniform functions
+	uniform := SpaceStats{Combinations: 1e6, Coincidence: 1e-6}
+	if got, want := uniform.NamesForProbability(0.5), NamesForProbability(0.5, 1e6); math.Abs(got-want) > 1e-9 {
+		t.Errorf("NamesForProbability(0.5) = %g, want %g", got, want)
+	}
+	if got := uniform.NamesForProbability(0.5); math.Abs(got-1177.41) > 0.01 {
+		t.Errorf("NamesForProbability(0.5) = %g, want about 1177.41", got)
+	}
+}
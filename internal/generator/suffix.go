@@ -0,0 +1,281 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type SuffixType string
+
+const (
+	SuffixAlpha     SuffixType = "alpha"
+	SuffixNumeric   SuffixType = "numeric"
+	SuffixMixed     SuffixType = "mixed"
+	SuffixTimestamp SuffixType = "timestamp"
+	SuffixULID      SuffixType = "ulid"
+	SuffixUUIDv7    SuffixType = "uuid7"
+	SuffixDate      SuffixType = "date"
+	SuffixDateTime  SuffixType = "datetime"
+	SuffixHex       SuffixType = "hex"
+	SuffixBase32    SuffixType = "base32"
+	SuffixSequence  SuffixType = "sequence"
+
+	SuffixPronounceable SuffixType = "pronounceable"
+)
+
+// crockfordAlphabet is Crockford's base32 alphabet in lowercase; it leaves out i, l, o and u
+const crockfordAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// SuffixSpec documents a suffix type and the lengths it accepts.
+// Lengths outside [MinLength, MaxLength] are clamped.
+type SuffixSpec struct {
+	Type        SuffixType
+	MinLength   int
+	MaxLength   int
+	Description string
+	Example     string
+}
+
+// DefaultSuffixLength is the suffix length used when none is given, raised to
+// the minimum of the types that need longer suffixes
+const DefaultSuffixLength = 4
+
+// DefaultLength returns the length of the suffix type when none is given
+func (s SuffixSpec) DefaultLength() int {
+	return min(max(DefaultSuffixLength, s.MinLength), s.MaxLength)
+}
+
+// suffixSpecs lists every suffix type in the order they are offered to users
+var suffixSpecs = []SuffixSpec{
+	{SuffixAlpha, 3, 8, "Alphabetic characters", "kqzv"},
+	{SuffixNumeric, 1, 6, "Digits, no leading zero", "4821"},
+	{SuffixMixed, 3, 8, "Letters and digits", "a1b2"},
+	{SuffixTimestamp, 8, 13, "Last digits of the Unix time in milliseconds", "30512345"},
+	{SuffixULID, 12, 26, "Sortable ULID: 10 time characters plus random characters", "01j9x2k7qmab"},
+	{SuffixUUIDv7, 20, 32, "Sortable UUIDv7 fragment in hex, without hyphens", "0192b6f1a3c47e21b9d4"},
+	{SuffixDate, 8, 8, "Date as YYYYMMDD, one name per day", "20250102"},
+	{SuffixDateTime, 11, 11, "Date and time as YYMMDD-HHMM, one name per minute", "250102-1504"},
+	{SuffixHex, 2, 16, "Hexadecimal digits", "3fa9"},
+	{SuffixBase32, 2, 16, "Crockford base32, no ambiguous letters", "7k2m"},
+	{SuffixSequence, 1, 8, "Next number of the series already on disk, zero-padded", "0042"},
+	{SuffixPronounceable, 2, 10, "Consonant-vowel syllables, optionally ending in digits", "bako"},
+}
+
+// SuffixTypes returns the specs of every suffix type
+func SuffixTypes() []SuffixSpec {
+	return suffixSpecs
+}
+
+// LookupSuffixSpec returns the spec of a suffix type
+func LookupSuffixSpec(suffixType SuffixType) (SuffixSpec, bool) {
+	for _, spec := range suffixSpecs {
+		if spec.Type == suffixType {
+			return spec, true
+		}
+	}
+	return SuffixSpec{}, false
+}
+
+// ParseSuffixType converts a user supplied suffix name into a SuffixType
+func ParseSuffixType(s string) (SuffixType, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	switch name {
+	case "alphabetic":
+		return SuffixAlpha, nil
+	case "uuidv7":
+		return SuffixUUIDv7, nil
+	case "crockford":
+		return SuffixBase32, nil
+	}
+
+	if _, ok := LookupSuffixSpec(SuffixType(name)); ok {
+		return SuffixType(name), nil
+	}
+
+	names := make([]string, 0, len(suffixSpecs))
+	for _, spec := range suffixSpecs {
+		names = append(names, string(spec.Type))
+	}
+	return "", fmt.Errorf("unknown suffix type %q (use %s)", s, strings.Join(names, ", "))
+}
+
+// clampSuffixLength forces length into the range supported by the suffix type
+func clampSuffixLength(suffixType SuffixType, length int) int {
+	spec, ok := LookupSuffixSpec(suffixType)
+	if !ok {
+		spec, _ = LookupSuffixSpec(SuffixMixed)
+	}
+	if length < spec.MinLength {
+		return spec.MinLength
+	}
+	if length > spec.MaxLength {
+		return spec.MaxLength
+	}
+	return length
+}
+
+// generateSuffixWithConfig generates suffix with specific configuration.
+// Sequence suffixes depend on the rest of the name and are rendered by the layout.
+func (g *Generator) generateSuffixWithConfig(suffixType SuffixType, length int) string {
+	length = clampSuffixLength(suffixType, length)
+
+	switch suffixType {
+	case SuffixAlpha:
+		return g.generateAlphaSuffixWithLength(length)
+	case SuffixNumeric:
+		return g.generateNumericSuffixWithLength(length)
+	case SuffixMixed:
+		return g.generateMixedSuffixWithLength(length)
+	case SuffixTimestamp:
+		return g.generateTimestampSuffix(length)
+	case SuffixULID:
+		return g.generateULIDSuffix(length)
+	case SuffixUUIDv7:
+		return g.generateUUIDv7Suffix(length)
+	case SuffixDate:
+		return "-" + g.now().Format("20060102")
+	case SuffixDateTime:
+		return "-" + g.now().Format("060102-1504")
+	case SuffixHex:
+		return "-" + g.randomString("0123456789abcdef", length)
+	case SuffixBase32:
+		if !g.config.IncludeNumbers {
+			return "-" + g.randomString(crockfordAlphabet[10:], length)
+		}
+		return "-" + g.randomString(crockfordAlphabet, length)
+	case SuffixPronounceable:
+		return g.generatePronounceableSuffixWithLength(length)
+	default:
+		return g.generateMixedSuffixWithLength(length)
+	}
+}
+
+// randomString draws length characters from chars
+func (g *Generator) randomString(chars string, length int) string {
+	var b strings.Builder
+	for i := 0; i < length; i++ {
+		b.WriteByte(chars[g.rand.Intn(len(chars))])
+	}
+	return b.String()
+}
+
+// tick returns the clock for a time-based suffix. Every call advances it by at
+// least a millisecond, so suffixes drawn in the same run never share a timestamp
+// and a run with a fixed Now is reproducible.
+func (g *Generator) tick() time.Time {
+	var t time.Time
+	if g.config.Now.IsZero() {
+		t = time.Now()
+		if !t.After(g.last) {
+			t = g.last.Add(time.Millisecond)
+		}
+	} else {
+		t = g.config.Now.Add(time.Duration(g.ticks) * time.Millisecond)
+		g.ticks++
+	}
+	g.last = t
+	return t
+}
+
+// generateTimestampSuffix keeps the last length digits of the Unix time in milliseconds
+func (g *Generator) generateTimestampSuffix(length int) string {
+	digits := fmt.Sprintf("%013d", g.tick().UnixMilli())
+	return "-" + digits[len(digits)-length:]
+}
+
+// generateULIDSuffix encodes a 48-bit millisecond timestamp as 10 Crockford base32
+// characters followed by length-10 random characters, so names sort by creation time
+func (g *Generator) generateULIDSuffix(length int) string {
+	ms := uint64(g.tick().UnixMilli())
+
+	var b strings.Builder
+	for shift := 45; shift >= 0; shift -= 5 {
+		b.WriteByte(crockfordAlphabet[(ms>>uint(shift))&0x1f])
+	}
+	b.WriteString(g.randomString(crockfordAlphabet, length-10))
+
+	return "-" + b.String()
+}
+
+// generateUUIDv7Suffix returns the first length hex digits of a UUIDv7: a 48-bit
+// millisecond timestamp, the version nibble 7, then random bits with the RFC 9562 variant
+func (g *Generator) generateUUIDv7Suffix(length int) string {
+	const hexChars = "0123456789abcdef"
+	ms := uint64(g.tick().UnixMilli()) & 0xffffffffffff
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%012x", ms))
+	b.WriteByte('7')
+	b.WriteString(g.randomString(hexChars, 3))
+	b.WriteByte(hexChars[8+g.rand.Intn(4)]) // variant 10xx
+	b.WriteString(g.randomString(hexChars, 15))
+
+	return "-" + b.String()[:length]
+}
+
+// SuffixSpace returns how many distinct suffixes a suffix type can produce at the given length.
+// Only random characters count: the time prefix of ULID and UUIDv7 suffixes is the same for
+// names made at once, and date suffixes only change once per day or minute, so they count as
+// a single value.
+func SuffixSpace(suffixType SuffixType, length int) uint64 {
+	length = clampSuffixLength(suffixType, length)
+
+	switch suffixType {
+	case SuffixAlpha:
+		return powSaturating(26, length)
+	case SuffixNumeric:
+		if length == 1 {
+			return 10
+		}
+		return 9 * powSaturating(10, length-1)
+	case SuffixTimestamp:
+		return powSaturating(10, length)
+	case SuffixULID:
+		// only the characters after the 10 time characters are random
+		return powSaturating(32, length-10)
+	case SuffixBase32:
+		return powSaturating(32, length)
+	case SuffixUUIDv7:
+		// 12 time digits and the version digit are fixed, and the variant
+		// digit after the next 3 random digits takes one of 4 values
+		return mulSaturating(4*powSaturating(16, 3), powSaturating(16, length-17))
+	case SuffixHex:
+		return powSaturating(16, length)
+	case SuffixDate, SuffixDateTime:
+		return 1
+	case SuffixSequence:
+		return powSaturating(10, length)
+	case SuffixPronounceable:
+		return pronounceableSpace(length, 0)
+	default:
+		return powSaturating(36, length)
+	}
+}
+
+// suffixSpace is SuffixSpace with the generator's IncludeNumbers and SuffixDigits settings applied
+func (g *Generator) suffixSpace(suffixType SuffixType, length int) uint64 {
+	length = clampSuffixLength(suffixType, length)
+	switch suffixType {
+	case SuffixPronounceable:
+		return pronounceableSpace(length, g.suffixDigits())
+	case SuffixMixed:
+		if !g.config.IncludeNumbers {
+			return powSaturating(26, length)
+		}
+	case SuffixBase32:
+		if !g.config.IncludeNumbers {
+			return powSaturating(22, length)
+		}
+	}
+	return SuffixSpace(suffixType, length)
+}
+
+// pronounceableSpace counts the pronounceable suffixes of length characters ending in digits digits
+func pronounceableSpace(length, digits int) uint64 {
+	letters, digits := pronounceableSplit(length, digits)
+	consonantCount := (letters + 1) / 2
+	space := mulSaturating(powSaturating(uint64(len(consonants)), consonantCount),
+		powSaturating(uint64(len(vowels)), letters-consonantCount))
+	return mulSaturating(space, powSaturating(10, digits))
+}
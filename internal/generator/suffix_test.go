@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuffixSpace(t *testing.T) {
+	tests := []struct {
+		suffixType SuffixType
+		length     int
+		want       uint64
+	}{
+		{SuffixAlpha, 4, 26 * 26 * 26 * 26},
+		{SuffixNumeric, 1, 10},
+		{SuffixNumeric, 3, 900},
+		{SuffixHex, 4, 1 << 16},
+		{SuffixBase32, 2, 32 * 32},
+		{SuffixULID, 12, 32 * 32},
+		{SuffixULID, 4, 32 * 32}, // clamped to the minimum of 12
+		{SuffixUUIDv7, 20, 4 * 16 * 16 * 16 * 16 * 16 * 16},
+		{SuffixUUIDv7, 17, 4 * 16 * 16 * 16 * 16 * 16 * 16}, // clamped to the minimum of 20
+		{SuffixUUIDv7, 32, math.MaxUint64},
+		{SuffixDate, 8, 1},
+		{SuffixDateTime, 11, 1},
+		{SuffixSequence, 3, 1000},
+	}
+
+	for _, tt := range tests {
+		if got := SuffixSpace(tt.suffixType, tt.length); got != tt.want {
+			t.Errorf("SuffixSpace(%s, %d) = %d, want %d", tt.suffixType, tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestSuffixDefaultLength(t *testing.T) {
+	tests := []struct {
+		suffixType SuffixType
+		want       int
+	}{
+		{SuffixAlpha, 4},
+		{SuffixNumeric, 4},
+		{SuffixTimestamp, 8},
+		{SuffixULID, 12},
+		{SuffixUUIDv7, 20},
+		{SuffixDate, 8},
+		{SuffixDateTime, 11},
+		{SuffixSequence, 4},
+	}
+
+	for _, tt := range tests {
+		spec, ok := LookupSuffixSpec(tt.suffixType)
+		if !ok {
+			t.Fatalf("no spec for %s", tt.suffixType)
+		}
+		if got := spec.DefaultLength(); got != tt.want {
+			t.Errorf("%s DefaultLength() = %d, want %d", tt.suffixType, got, tt.want)
+		}
+	}
+}
+
+func TestSuffixLengths(t *testing.T) {
+	g := NewGenerator(Config{Seed: 1, IncludeNumbers: true, Now: time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)})
+
+	for _, spec := range SuffixTypes() {
+		if spec.Type == SuffixSequence {
+			continue
+		}
+		for _, length := range []int{spec.MinLength, spec.MaxLength} {
+			suffix := g.generateSuffixWithConfig(spec.Type, length)
+			if !strings.HasPrefix(suffix, "-") || len(suffix)-1 != length {
+				t.Errorf("%s suffix of length %d = %q", spec.Type, length, suffix)
+			}
+		}
+	}
+}
+
+func TestPronounceableSuffix(t *testing.T) {
+	tests := []struct {
+		name           string
+		length         int
+		digits         int
+		includeNumbers bool
+		wantLetters    int
+		wantDigits     int
+	}{
+		{"letters only", 4, 0, true, 4, 0},
+		{"odd length", 5, 0, true, 5, 0},
+		{"trailing digits", 6, 2, true, 4, 2},
+		{"digits need IncludeNumbers", 6, 2, false, 6, 0},
+		{"one syllable is kept", 4, 5, true, 2, 2},
+		{"clamped to the minimum", 1, 0, true, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(Config{Seed: 8, IncludeNumbers: tt.includeNumbers, SuffixDigits: tt.digits})
+			for i := 0; i < 50; i++ {
+				suffix := strings.TrimPrefix(g.generateSuffixWithConfig(SuffixPronounceable, tt.length), "-")
+				if len(suffix) != tt.wantLetters+tt.wantDigits {
+					t.Fatalf("suffix %q has %d characters, want %d", suffix, len(suffix), tt.wantLetters+tt.wantDigits)
+				}
+				for j, c := range suffix {
+					var chars string
+					switch {
+					case j >= tt.wantLetters:
+						chars = "0123456789"
+					case j%2 == 0:
+						chars = consonants
+					default:
+						chars = vowels
+					}
+					if !strings.ContainsRune(chars, c) {
+						t.Fatalf("suffix %q has %q at %d, want one of %q", suffix, c, j, chars)
+					}
+				}
+				if !matchesSuffix(SuffixPronounceable, suffix) {
+					t.Errorf("matchesSuffix(pronounceable, %q) = false", suffix)
+				}
+			}
+
+			want := pronounceableSpace(tt.wantLetters+tt.wantDigits, tt.wantDigits)
+			if got := g.suffixSpace(SuffixPronounceable, tt.length); got != want {
+				t.Errorf("suffixSpace() = %d, want %d", got, want)
+			}
+		})
+	}
+
+	// "bako": two consonants and two vowels, "bak42": two consonants, a vowel and two digits
+	if got := pronounceableSpace(4, 0); got != 16*5*16*5 {
+		t.Errorf("pronounceableSpace(4, 0) = %d, want %d", got, 16*5*16*5)
+	}
+	if got := pronounceableSpace(5, 2); got != 16*5*16*100 {
+		t.Errorf("pronounceableSpace(5, 2) = %d, want %d", got, 16*5*16*100)
+	}
+}
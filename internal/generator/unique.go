@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// defaultMaxAttempts bounds how often a colliding name is re-rolled
+const defaultMaxAttempts = 100
+
+// ErrNameSpaceExhausted is matched by errors.Is for every *ExhaustedError
+var ErrNameSpaceExhausted = errors.New("name space exhausted")
+
+// ExhaustedError is returned when no unused name could be found within the retry budget
+type ExhaustedError struct {
+	Category     string
+	SuffixType   SuffixType
+	SuffixLength int
+	Attempts     int
+	Total        uint64 // distinct names the layout can produce
+	Remaining    uint64 // Total minus the names of the layout already issued or found on disk
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("name space exhausted for category '%s' with %s suffix (length %d): %d of %d combinations remain after %d attempts",
+		e.Category, e.SuffixType, e.SuffixLength, e.Remaining, e.Total, e.Attempts)
+}
+
+// Is makes errors.Is(err, ErrNameSpaceExhausted) work
+func (e *ExhaustedError) Is(target error) bool {
+	return target == ErrNameSpaceExhausted
+}
+
+// isTaken reports whether name was already issued by this generator or exists in the target directory
+func (g *Generator) isTaken(name string) bool {
+	if _, ok := g.issued[name]; ok {
+		return true
+	}
+	if _, ok := g.onDisk[name]; ok {
+		return true
+	}
+
+	if g.config.SkipDiskCheck {
+		return false
+	}
+
+	if _, err := os.Lstat(filepath.Join(g.targetDirOrDefault(), name)); err == nil {
+		g.onDisk[name] = struct{}{}
+		return true
+	}
+
+	return false
+}
+
+// nameSet is a set of names
+type nameSet map[string]struct{}
+
+// spaceKey identifies the name space of a layout and selection
+func spaceKey(layout *Layout, sel Selection, suffixType SuffixType, length int) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%d", layout, sel.Frontend, sel.Backend, sel.Category, sel.Word,
+		suffixType, clampSuffixLength(suffixType, length))
+}
+
+// markTaken records that the name space identified by key produced a name that is now taken
+func (g *Generator) markTaken(key, name string) {
+	if g.taken[key] == nil {
+		g.taken[key] = make(nameSet)
+	}
+	g.taken[key][name] = struct{}{}
+}
+
+// newExhaustedError reports how much of the name space is left for a failed selection.
+// Only taken names the space has produced count as used: names issued for other
+// layouts or categories, and unrelated entries of the target directory, do not.
+func (g *Generator) newExhaustedError(layout *Layout, sel Selection, suffixType SuffixType, length, attempts int) *ExhaustedError {
+	total := g.nameSpace(layout, sel, suffixType, length)
+	used := uint64(len(g.taken[spaceKey(layout, sel, suffixType, length)]))
+
+	remaining := uint64(0)
+	if total > used {
+		remaining = total - used
+	}
+
+	return &ExhaustedError{
+		Category:     sel.Category,
+		SuffixType:   suffixType,
+		SuffixLength: clampSuffixLength(suffixType, length),
+		Attempts:     attempts,
+		Total:        total,
+		Remaining:    remaining,
+	}
+}
+
+// nameSpace counts the distinct names a layout can produce for a selection
+func (g *Generator) nameSpace(layout *Layout, sel Selection, suffixType SuffixType, length int) uint64 {
+	total := uint64(1)
+
+	if sel.Word == "" && layout.Uses(TokenWord) {
+		if g.config.Composition != nil {
+			total = mulSaturating(total, g.compositionSpace(sel.Category))
+		} else {
+			total = mulSaturating(total, uint64(g.wordSpace(sel.Category)))
+		}
+	}
+
+	for _, t := range layout.tokens {
+		if t.Kind != TokenSuffix {
+			continue
+		}
+		st, sl := suffixTokenConfig(t, suffixType, length)
+		total = mulSaturating(total, g.suffixSpace(st, sl))
+	}
+
+	return total
+}
+
+// wordSpace counts the distinct words that can be picked for a category
+func (g *Generator) wordSpace(category string) int {
+	words := make(map[string]struct{})
+	for _, cat := range g.candidateCategories(category) {
+		list := g.words(cat)
+		if len(list) == 0 {
+			words["folder"] = struct{}{}
+		}
+		for _, w := range list {
+			words[w] = struct{}{}
+		}
+	}
+
+	return len(words)
+}
+
+func powSaturating(base uint64, exp int) uint64 {
+	result := uint64(1)
+	for i := 0; i < exp; i++ {
+		result = mulSaturating(result, base)
+	}
+	return result
+}
+
+func mulSaturating(a, b uint64) uint64 {
+	if a != 0 && b > math.MaxUint64/a {
+		return math.MaxUint64
+	}
+	return a * b
+}
@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateUnique(t *testing.T) {
+	g := NewGenerator(Config{
+		Seed:           3,
+		Categories:     map[string][]string{"food": {"pizza", "taco"}},
+		SuffixType:     SuffixHex,
+		SuffixLength:   2,
+		IncludeNumbers: true,
+		Count:          400, // most of the 512 names, so collisions are re-rolled
+		SkipDiskCheck:  true,
+	})
+	names, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("%q was generated twice", name)
+		}
+		seen[name] = true
+	}
+}
+
+func TestGenerateRerollsTakenNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"pizza", "taco"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	layout := MustParseLayout("{word}")
+	g := NewGenerator(Config{
+		Seed:       5,
+		Categories: map[string][]string{"food": {"pizza", "taco", "pie", "kale"}},
+		Layout:     layout,
+		Count:      1,
+		TargetDir:  dir,
+	})
+	g.Reserve("pie")
+
+	names, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(names) != 1 || names[0] != "kale" {
+		t.Fatalf("Generate() = %v, want [kale]", names)
+	}
+
+	_, err = g.GenerateName(layout, Selection{Category: "food"}, SuffixMixed, 4)
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) || exhausted.Total != 4 || exhausted.Remaining != 0 {
+		t.Fatalf("GenerateName() error = %v, want none of 4 combinations remaining", err)
+	}
+}
+
+func TestExhaustedError(t *testing.T) {
+	layout := MustParseLayout("{word}")
+	g := NewGenerator(Config{
+		Seed: 9,
+		Categories: map[string][]string{
+			"food":  {"pizza", "taco", "pie"},
+			"space": {"comet", "nova"},
+		},
+		MaxAttempts:   50,
+		SkipDiskCheck: true,
+	})
+	// Names outside the food space must not count against it
+	g.Reserve("unrelated", "comet")
+	if _, err := g.GenerateName(layout, Selection{Category: "space"}, SuffixMixed, 4); err != nil {
+		t.Fatalf("GenerateName(space): %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := g.GenerateName(layout, Selection{Category: "food"}, SuffixMixed, 4); err != nil {
+			t.Fatalf("GenerateName(food) %d: %v", i, err)
+		}
+	}
+
+	_, err := g.GenerateName(layout, Selection{Category: "food"}, SuffixMixed, 4)
+	if !errors.Is(err, ErrNameSpaceExhausted) {
+		t.Fatalf("GenerateName() error = %v, want ErrNameSpaceExhausted", err)
+	}
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("GenerateName() error = %v, want an *ExhaustedError", err)
+	}
+	if exhausted.Category != "food" || exhausted.Attempts != 50 || exhausted.Total != 3 || exhausted.Remaining != 0 {
+		t.Errorf("error = %+v, want category food, 50 attempts, 3 combinations and none remaining", *exhausted)
+	}
+
+	// Part of the space is still free
+	g = NewGenerator(Config{Categories: map[string][]string{"food": {"pizza", "taco", "pie"}}, SkipDiskCheck: true})
+	g.Reserve("unrelated")
+	if _, err := g.GenerateName(layout, Selection{Category: "food"}, SuffixMixed, 4); err != nil {
+		t.Fatalf("GenerateName: %v", err)
+	}
+	if e := g.newExhaustedError(layout, Selection{Category: "food"}, SuffixMixed, 4, 1); e.Total != 3 || e.Remaining != 2 {
+		t.Errorf("newExhaustedError() = %+v, want 2 of 3 combinations remaining", *e)
+	}
+}
@@ -0,0 +1,111 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Session records everything needed to regenerate the names of a single run
+type Session struct {
+	Command      string              `yaml:"command"` // generate or interactive
+	Seed         int64               `yaml:"seed"`
+	Time         time.Time           `yaml:"time"` // clock used for {date} tokens and timestamp suffixes
+	Layout       string              `yaml:"layout,omitempty"`
+	Frontend     string              `yaml:"frontend,omitempty"`
+	Backend      string              `yaml:"backend,omitempty"`
+	Category     string              `yaml:"category"`
+	Word         string              `yaml:"word,omitempty"` // custom word typed in interactive mode
+	Sampling     string              `yaml:"sampling,omitempty"`
+	Compose      string              `yaml:"compose,omitempty"`
+	Case         string              `yaml:"case,omitempty"`
+	Separator    string              `yaml:"separator,omitempty"`
+	MaxLength    int                 `yaml:"max_length,omitempty"`
+	MaxWord      int                 `yaml:"max_word_length,omitempty"`
+	MaxCode      int                 `yaml:"max_code_length,omitempty"`
+	NoSFW        bool                `yaml:"no_sfw,omitempty"` // built-in safe for work filter was disabled
+	Emojis       bool                `yaml:"emojis,omitempty"`
+	NoNumbers    bool                `yaml:"no_numbers,omitempty"`
+	Portability  string              `yaml:"portability,omitempty"`
+	Alliterate   string              `yaml:"alliterate,omitempty"`
+	WordMatch    string              `yaml:"word_match,omitempty"`
+	WordLists    map[string][]string `yaml:"word_lists,omitempty"` // words read with --words-from, by category
+	SuffixType   string              `yaml:"suffix_type"`
+	SuffixLength int                 `yaml:"suffix_length"`
+	SuffixDigits int                 `yaml:"suffix_digits,omitempty"` // trailing digits of pronounceable suffixes
+	Count        int                 `yaml:"count"`
+	Reserved     []string            `yaml:"reserved,omitempty"`  // names skipped because they already existed on disk
+	Sequences    map[string]int      `yaml:"sequences,omitempty"` // highest existing number per sequence prefix
+	Names        []string            `yaml:"names"`               // empty for runs streamed as ndjson
+}
+
+// maxSessionFiles bounds the numbered files tried for runs that would share a name
+const maxSessionFiles = 100
+
+// Save writes the session into dir and returns the path of the new file. It
+// never overwrites an existing session.
+func Save(s *Session, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	header := "# dir-init session, replay with: dir-init replay <this file>\n\n"
+	base := fmt.Sprintf("%s-%s-%d", s.Time.Format("20060102-150405"), s.Command, s.Seed)
+
+	// Runs in the same second with the same seed get numbered files instead of
+	// overwriting each other
+	for i := 1; i <= maxSessionFiles; i++ {
+		name := base + ".yaml"
+		if i > 1 {
+			name = fmt.Sprintf("%s-%d.yaml", base, i)
+		}
+		path := filepath.Join(dir, name)
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write session file: %w", err)
+		}
+		_, err = f.Write(append([]byte(header), data...))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write session file: %w", err)
+		}
+		return path, nil
+	}
+
+	return "", fmt.Errorf("failed to write session file: %d sessions named %s already exist", maxSessionFiles, base)
+}
+
+// Load reads a session file written by Save
+func Load(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var s Session
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse session file: %w", err)
+	}
+
+	if s.Seed == 0 {
+		return nil, fmt.Errorf("session file %s has no seed", path)
+	}
+
+	return &s, nil
+}
@@ -0,0 +1,133 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		session  Session
+		wantFile string
+	}{
+		{
+			name: "generate",
+			session: Session{
+				Command:      "generate",
+				Seed:         42,
+				Time:         now,
+				Category:     "food",
+				SuffixType:   "mixed",
+				SuffixLength: 4,
+				Count:        2,
+				Names:        []string{"pizza-a1b2", "taco-c3d4"},
+			},
+			wantFile: "20250102-150405-generate-42.yaml",
+		},
+		{
+			name: "interactive",
+			session: Session{
+				Command:      "interactive",
+				Seed:         -7,
+				Time:         now,
+				Layout:       "{frontend}-{backend}-{word}{suffix}",
+				Frontend:     "rct",
+				Backend:      "node",
+				Category:     "all",
+				Word:         "custom",
+				SuffixType:   "sequence",
+				SuffixLength: 4,
+				Count:        1,
+				WordLists:    map[string][]string{"food": {"pizza"}},
+				Sequences:    map[string]int{"rct-node-custom": 3},
+				Names:        []string{"rct-node-custom-0004"},
+			},
+			wantFile: "20250102-150405-interactive--7.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "sessions")
+
+			path, err := Save(&tt.session, dir)
+			if err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			if filepath.Base(path) != tt.wantFile {
+				t.Errorf("Save() wrote %s, want %s", filepath.Base(path), tt.wantFile)
+			}
+
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if !reflect.DeepEqual(*loaded, tt.session) {
+				t.Errorf("Load() = %+v, want %+v", *loaded, tt.session)
+			}
+		})
+	}
+}
+
+func TestSaveSameName(t *testing.T) {
+	dir := t.TempDir()
+	s := Session{Command: "generate", Seed: 42, Time: time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC), Count: 1}
+
+	want := []string{
+		"20250102-150405-generate-42.yaml",
+		"20250102-150405-generate-42-2.yaml",
+		"20250102-150405-generate-42-3.yaml",
+	}
+	for i, wantFile := range want {
+		s.Names = []string{fmt.Sprintf("pizza-%04d", i)}
+		path, err := Save(&s, dir)
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		if filepath.Base(path) != wantFile {
+			t.Errorf("Save() wrote %s, want %s", filepath.Base(path), wantFile)
+		}
+		loaded, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if !reflect.DeepEqual(loaded.Names, s.Names) {
+			t.Errorf("Load(%s) names = %v, want %v", filepath.Base(path), loaded.Names, s.Names)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"no seed", "command: generate\ncount: 1\n", "has no seed"},
+		{"invalid YAML", "seed: [1\n", "failed to parse session file"},
+		{"wrong type", "seed: many\n", "failed to parse session file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.yaml")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Errorf("Load() of a missing file succeeded")
+	}
+}
@@ -57,6 +57,50 @@ func IsValidDirectoryName(name string) bool {
 	return true
 }
 
+// Portability names a set of rules a directory name must follow on top of
+// IsValidDirectoryName, so names can be moved to stricter filesystems and tools
+type Portability string
+
+const (
+	PortabilityNative Portability = "native" // anything IsValidDirectoryName accepts, including emoji
+	PortabilityASCII  Portability = "ascii"  // printable ASCII only
+	PortabilityPOSIX  Portability = "posix"  // the POSIX portable filename characters A-Z a-z 0-9 . _ -
+)
+
+// ParsePortability converts a user supplied profile name into a Portability
+func ParsePortability(s string) (Portability, error) {
+	switch p := Portability(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return PortabilityNative, nil
+	case PortabilityNative, PortabilityASCII, PortabilityPOSIX:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown portability profile %q (use native, ascii or posix)", s)
+}
+
+// IsPortableName checks a directory name against a portability profile
+func IsPortableName(name string, profile Portability) bool {
+	if !IsValidDirectoryName(name) {
+		return false
+	}
+
+	for _, r := range name {
+		switch profile {
+		case PortabilityASCII:
+			if r < 0x20 || r > 0x7e {
+				return false
+			}
+		case PortabilityPOSIX:
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+				return false
+			}
+		}
+	}
+
+	// A leading hyphen makes the name look like an option to POSIX tools
+	return profile != PortabilityPOSIX || !strings.HasPrefix(name, "-")
+}
+
 // SanitizeDirectoryName cleans a directory name to make it filesystem-safe
 func SanitizeDirectoryName(name string) string {
 	// Replace invalid characters with underscores
@@ -94,9 +138,10 @@ func SanitizeDirectoryName(name string) string {
 	return sanitized
 }
 
-// CreateDirectory safely creates a directory with validation
+// CreateDirectory safely creates a directory with validation.
+// It fails with an error wrapping os.ErrExist if the directory is already there.
 func CreateDirectory(path string) error {
-	if !IsValidDirectoryName(path) {
+	if !IsValidDirectoryName(filepath.Base(path)) {
 		return fmt.Errorf("invalid directory name: %s", path)
 	}
 
@@ -109,8 +154,11 @@ func CreateDirectory(path string) error {
 		}
 	}
 
-	// Create the directory
-	err := os.MkdirAll(path, 0755)
+	// Create the directory, refusing to reuse one that already exists
+	err := os.Mkdir(path, 0755)
+	if os.IsExist(err) {
+		return fmt.Errorf("directory already exists: %s: %w", path, os.ErrExist)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create directory: %v", err)
 	}
@@ -124,13 +172,16 @@ func DirectoryExists(path string) bool {
 	return err == nil
 }
 
-// ValidateDirectoryCount checks if the requested number of directories is reasonable
+// MaxDirectoryCount is the largest number of names a single run can ask for
+const MaxDirectoryCount = 1_000_000
+
+// ValidateDirectoryCount checks if the requested number of directories is between 1 and MaxDirectoryCount
 func ValidateDirectoryCount(count int) error {
 	if count < 1 {
 		return fmt.Errorf("count must be at least 1")
 	}
-	if count > 20 {
-		return fmt.Errorf("count cannot exceed 20")
+	if count > MaxDirectoryCount {
+		return fmt.Errorf("count must be at most %d", MaxDirectoryCount)
 	}
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsPortableName(t *testing.T) {
+	tests := []struct {
+		name   string
+		native bool
+		ascii  bool
+		posix  bool
+	}{
+		{"pizza-a1b2", true, true, true},
+		{"pizza_night.v2", true, true, true},
+		{"🍕-pizza-a1b2", true, false, false},
+		{"café-0001", true, false, false},
+		{"pizza night", true, true, false},
+		{"pizza+taco", true, true, false},
+		{"-pizza", true, true, false},
+		{"pizza/taco", false, false, false},
+		{"CON", false, false, false},
+		{".hidden", false, false, false},
+		{"", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for profile, want := range map[Portability]bool{
+				PortabilityNative: tt.native,
+				PortabilityASCII:  tt.ascii,
+				PortabilityPOSIX:  tt.posix,
+			} {
+				if got := IsPortableName(tt.name, profile); got != want {
+					t.Errorf("IsPortableName(%q, %s) = %v, want %v", tt.name, profile, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePortability(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Portability
+		wantErr bool
+	}{
+		{"", PortabilityNative, false},
+		{"native", PortabilityNative, false},
+		{" ASCII ", PortabilityASCII, false},
+		{"posix", PortabilityPOSIX, false},
+		{"dos", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePortability(tt.input)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParsePortability(%q) = %q, %v, want %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCreateDirectory(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "pizza-a1b2")
+
+	if err := CreateDirectory(path); err != nil {
+		t.Fatalf("CreateDirectory: %v", err)
+	}
+	if !DirectoryExists(path) {
+		t.Fatalf("%s was not created", path)
+	}
+	if err := CreateDirectory(path); !errors.Is(err, os.ErrExist) {
+		t.Errorf("CreateDirectory() of an existing directory = %v, want os.ErrExist", err)
+	}
+	if err := CreateDirectory(filepath.Join(dir, "pizza?")); err == nil {
+		t.Errorf("CreateDirectory() accepted an invalid name")
+	}
+}
@@ -0,0 +1,180 @@
+package dirinit
+
+import (
+	"github.com/aravindcm49/dir-init/internal/config"
+)
+
+// Config is the dir-init config: categories, frontends, backends and naming
+// defaults. LoadConfig reads the one of the CLI; programs can also build a
+// Config themselves and pass it with WithConfig.
+type Config struct {
+	Categories map[string]Category
+	Frontends  []Frontend
+	Backends   []Backend
+	Layout     string
+	Sampling   string
+	Compose    string
+	Case       string
+	Separator  string
+
+	MaxLength     int // longest generated name, 0 means unlimited
+	MaxWordLength int // longer words are skipped
+	MaxCodeLength int // longer frontend and backend codes are abbreviated
+	SuffixDigits  int // trailing digits of pronounceable suffixes
+
+	Blocklist   Blocklist
+	SafeForWork *bool // built-in filter, on unless set to false
+
+	Emojis         bool   // prefix names with the emoji of their word
+	IncludeNumbers *bool  // digits in suffixes, on unless set to false
+	Portability    string // native, ascii or posix
+	Alliterate     string // any, frontend, backend or both
+}
+
+// Category is a named word list. Description, examples and order only affect
+// how categories are listed; hidden categories are left out of "all".
+type Category struct {
+	Description string
+	Examples    []string
+	Hidden      bool
+	Order       int    // lower first, unordered categories last
+	Pos         string // part of speech for composition patterns: adjective, noun or verb
+	Weight      float64
+	Words       []string
+	WordWeights map[string]float64
+	Emoji       map[string]string // word -> emoji shown with WithEmojis
+}
+
+// Frontend is a frontend code of the {frontend} layout token, such as "rct"
+type Frontend struct {
+	Code        string
+	Description string
+}
+
+// Backend is a backend code of the {backend} layout token, such as "node"
+type Backend struct {
+	Code        string
+	Description string
+}
+
+// Blocklist lists words and substrings that generated names must not contain
+type Blocklist struct {
+	Words      []string // whole words, matched ignoring case
+	Substrings []string // matched anywhere in words and suffixes
+}
+
+// LoadConfig loads the config of the dir-init CLI, merged from the system,
+// user and project config files and the DIR_INIT_* environment variables.
+// Nothing is written: a missing user config reads as the defaults, and older
+// files are upgraded in memory.
+func LoadConfig() (*Config, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return fromConfig(cfg), nil
+}
+
+// LoadConfigFile loads the config like LoadConfig, with path as the user
+// config file, in YAML, JSON or TOML by its extension, and the words
+// directory next to it as its word lists
+func LoadConfigFile(path string) (*Config, error) {
+	cfg, err := config.NewStore(config.FilePaths(path)).LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return fromConfig(cfg), nil
+}
+
+// DefaultConfig returns the built-in config the CLI uses without a config file
+func DefaultConfig() *Config {
+	return fromConfig(config.DefaultConfig())
+}
+
+// Categories returns the names of the categories "all" picks from, in the
+// display order of the config. Hidden categories are left out.
+func Categories(cfg *Config) []string {
+	return cfg.internal().CategoryNames(false)
+}
+
+func fromConfig(c *config.Config) *Config {
+	cfg := &Config{
+		Categories:     make(map[string]Category, len(c.Categories)),
+		Layout:         c.Layout,
+		Sampling:       c.Sampling,
+		Compose:        c.Compose,
+		Case:           c.Case,
+		Separator:      c.Separator,
+		MaxLength:      c.MaxLength,
+		MaxWordLength:  c.MaxWordLength,
+		MaxCodeLength:  c.MaxCodeLength,
+		SuffixDigits:   c.SuffixDigits,
+		Blocklist:      Blocklist(c.Blocklist),
+		SafeForWork:    c.SafeForWork,
+		Emojis:         c.Emojis,
+		IncludeNumbers: c.IncludeNumbers,
+		Portability:    c.Portability,
+		Alliterate:     c.Alliterate,
+	}
+	for name, cat := range c.Categories {
+		cfg.Categories[name] = Category{
+			Description: cat.Description,
+			Examples:    cat.Examples,
+			Hidden:      cat.Hidden,
+			Order:       cat.Order,
+			Pos:         cat.Pos,
+			Weight:      cat.Weight,
+			Words:       cat.Words,
+			WordWeights: cat.WordWeights,
+			Emoji:       cat.Emoji,
+		}
+	}
+	for _, fe := range c.Frontends {
+		cfg.Frontends = append(cfg.Frontends, Frontend(fe))
+	}
+	for _, be := range c.Backends {
+		cfg.Backends = append(cfg.Backends, Backend(be))
+	}
+	return cfg
+}
+
+// internal converts the config for the generator
+func (c *Config) internal() *config.Config {
+	cfg := config.NewConfig()
+	cfg.Layout = c.Layout
+	cfg.Sampling = c.Sampling
+	cfg.Compose = c.Compose
+	cfg.Case = c.Case
+	cfg.Separator = c.Separator
+	cfg.MaxLength = c.MaxLength
+	cfg.MaxWordLength = c.MaxWordLength
+	cfg.MaxCodeLength = c.MaxCodeLength
+	cfg.SuffixDigits = c.SuffixDigits
+	cfg.Blocklist = config.Blocklist(c.Blocklist)
+	cfg.SafeForWork = c.SafeForWork
+	cfg.Emojis = c.Emojis
+	cfg.IncludeNumbers = c.IncludeNumbers
+	cfg.Portability = c.Portability
+	cfg.Alliterate = c.Alliterate
+
+	for name, cat := range c.Categories {
+		cfg.Categories[name] = config.Category{
+			Description: cat.Description,
+			Examples:    cat.Examples,
+			Hidden:      cat.Hidden,
+			Order:       cat.Order,
+			Pos:         cat.Pos,
+			Weight:      cat.Weight,
+			Words:       cat.Words,
+			WordWeights: cat.WordWeights,
+			Emoji:       cat.Emoji,
+		}
+	}
+	for _, fe := range c.Frontends {
+		cfg.Frontends = append(cfg.Frontends, config.Frontend(fe))
+	}
+	for _, be := range c.Backends {
+		cfg.Backends = append(cfg.Backends, config.Backend(be))
+	}
+	return cfg
+}
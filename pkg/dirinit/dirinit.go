@@ -0,0 +1,464 @@
+package dirinit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"regexp"
+	"time"
+
+	"github.com/aravindcm49/dir-init/internal/config"
+	"github.com/aravindcm49/dir-init/internal/generator"
+	"github.com/aravindcm49/dir-init/internal/utils"
+)
+
+const (
+	// DefaultLayout is used when neither WithLayout nor the config file sets a layout
+	DefaultLayout = generator.DefaultLayout
+
+	// EnhancedLayout includes the frontend and backend codes, as used by the interactive CLI
+	EnhancedLayout = generator.DefaultEnhancedLayout
+)
+
+// SuffixType names a kind of suffix, such as "mixed" or "ulid"
+type SuffixType = generator.SuffixType
+
+// SuffixSpec documents a suffix type and the lengths it accepts
+type SuffixSpec = generator.SuffixSpec
+
+// SuffixTypes returns the specs of every suffix type, in the order they are offered to users
+func SuffixTypes() []SuffixSpec {
+	return generator.SuffixTypes()
+}
+
+// ParseSuffix returns the spec of a suffix type name, accepting aliases such as "uuidv7"
+func ParseSuffix(name string) (SuffixSpec, error) {
+	suffixType, err := generator.ParseSuffixType(name)
+	if err != nil {
+		return SuffixSpec{}, err
+	}
+	spec, _ := generator.LookupSuffixSpec(suffixType)
+	return spec, nil
+}
+
+// Rejections counts the words and suffixes re-rolled because the blocklist matched them
+type Rejections = generator.Rejections
+
+// Parsed holds the components recovered from a name by Parse
+type Parsed = generator.ParsedName
+
+// ParsedSuffix is a suffix recovered by Parse, with the types that can produce it
+type ParsedSuffix = generator.ParsedSuffix
+
+// Stats describes how many distinct names a category can produce
+type Stats = generator.SpaceStats
+
+// CollisionProbability returns the birthday bound for the chance that n random
+// names drawn from space combinations are not all distinct, assuming every
+// combination is equally likely. Stats.CollisionProbability uses the real
+// probabilities of the names of a category.
+func CollisionProbability(n int, space uint64) float64 {
+	return generator.CollisionProbability(n, space)
+}
+
+// NamesForProbability returns how many random names give a collision chance of
+// p when every one of space combinations is equally likely
+func NamesForProbability(p float64, space uint64) float64 {
+	return generator.NamesForProbability(p, space)
+}
+
+// Settings are the effective values a Generator uses, after merging the
+// options with the config file. Passing them back as options reproduces a run.
+type Settings struct {
+	Category       string
+	SuffixType     string
+	SuffixLength   int
+	Count          int
+	Seed           int64
+	Layout         string
+	Frontend       string
+	Backend        string
+	Word           string
+	TargetDir      string
+	Now            time.Time
+	Sampling       string
+	Compose        string
+	Case           string
+	Separator      string
+	MaxLength      int
+	MaxWordLength  int
+	MaxCodeLength  int
+	SuffixDigits   int
+	Emojis         bool
+	IncludeNumbers bool
+	SafeForWork    bool
+	Portability    string
+	Alliterate     string
+	WordMatch      string
+	WordLists      map[string][]string // words added with WithWordList, by category
+}
+
+// Generator generates unique names. It is safe for concurrent use: names are
+// unique across every goroutine sharing a Generator. With a fixed seed the
+// output is only reproducible when a single goroutine draws the names.
+type Generator struct {
+	gen        *generator.Generator
+	layout     *generator.Layout
+	sel        generator.Selection
+	settings   Settings
+	suffixHint generator.SuffixType
+}
+
+// New builds a Generator from the options, reading the CLI config like
+// LoadConfig unless WithConfig is given. Invalid options are reported as
+// *OptionError.
+func New(opts ...Option) (*Generator, error) {
+	s := defaultSettings()
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	var cfg *config.Config
+	if s.config != nil {
+		cfg = s.config.internal()
+	} else {
+		var err error
+		if cfg, err = config.LoadConfig(); err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+
+	for category, words := range s.wordLists {
+		if category == "" || category == "all" {
+			return nil, &OptionError{Option: "word-list", Err: fmt.Errorf("invalid category name %q", category)}
+		}
+		for _, w := range words {
+			if err := config.ValidateWord(w); err != nil {
+				return nil, &OptionError{Option: "word-list", Err: err}
+			}
+		}
+		cfg = cfg.WithWords(category, words)
+	}
+
+	genConfig, err := generatorConfig(cfg, &s)
+	if err != nil {
+		return nil, err
+	}
+
+	layoutText := firstNonEmpty(s.layout, cfg.Layout, DefaultLayout)
+	layout, err := generator.ParseLayout(layoutText)
+	if err != nil {
+		return nil, &OptionError{Option: "layout", Err: err}
+	}
+	genConfig.Layout = layout
+
+	suffixType, err := generator.ParseSuffixType(s.suffixType)
+	if err != nil {
+		return nil, &OptionError{Option: "suffix", Err: err}
+	}
+	spec, _ := generator.LookupSuffixSpec(suffixType)
+	genConfig.SuffixType = suffixType
+	genConfig.SuffixLength = spec.DefaultLength()
+	if s.suffixLength != 0 {
+		genConfig.SuffixLength = min(max(s.suffixLength, spec.MinLength), spec.MaxLength)
+	}
+
+	if err := utils.ValidateDirectoryCount(s.count); err != nil {
+		return nil, &OptionError{Option: "count", Err: err}
+	}
+
+	genConfig.Category = s.category
+	genConfig.Count = s.count
+	genConfig.Seed = s.seed
+	genConfig.Frontend = s.frontend
+	genConfig.Backend = s.backend
+	genConfig.TargetDir = s.targetDir
+	genConfig.Now = s.now
+	genConfig.SkipDiskCheck = s.skipDiskCheck
+	genConfig.SequenceStarts = s.sequenceStarts
+	if s.maxAttempts > 0 {
+		genConfig.MaxAttempts = s.maxAttempts
+	}
+
+	gen := generator.NewGenerator(genConfig)
+	if err := gen.CheckCategory(s.category); err != nil {
+		return nil, &OptionError{Option: "category", Err: err}
+	}
+	gen.Reserve(s.reserved...)
+
+	compose := ""
+	if genConfig.Composition != nil {
+		compose = genConfig.Composition.String()
+	}
+
+	var suffixHint generator.SuffixType
+	if s.suffixSet {
+		suffixHint = suffixType
+	}
+
+	return &Generator{
+		gen:    gen,
+		layout: layout,
+		sel: generator.Selection{
+			Frontend: s.frontend,
+			Backend:  s.backend,
+			Category: s.category,
+			Word:     s.word,
+		},
+		settings: Settings{
+			Category:       s.category,
+			SuffixType:     string(suffixType),
+			SuffixLength:   genConfig.SuffixLength,
+			Count:          s.count,
+			Seed:           gen.Seed(),
+			Layout:         layout.String(),
+			Frontend:       s.frontend,
+			Backend:        s.backend,
+			Word:           s.word,
+			TargetDir:      s.targetDir,
+			Now:            s.now,
+			Sampling:       string(genConfig.Sampling),
+			Compose:        compose,
+			Case:           string(genConfig.CaseStyle),
+			Separator:      genConfig.Separator,
+			MaxLength:      genConfig.MaxLength,
+			MaxWordLength:  genConfig.MaxWordLength,
+			MaxCodeLength:  genConfig.MaxCodeLength,
+			SuffixDigits:   genConfig.SuffixDigits,
+			Emojis:         genConfig.UseEmojis,
+			IncludeNumbers: genConfig.IncludeNumbers,
+			SafeForWork:    genConfig.Blocklist.SafeForWork(),
+			Portability:    string(genConfig.Portability),
+			Alliterate:     string(genConfig.Alliteration),
+			WordMatch:      s.wordMatch,
+			WordLists:      s.wordLists,
+		},
+		suffixHint: suffixHint,
+	}, nil
+}
+
+// generatorConfig merges the naming options with the config file
+func generatorConfig(cfg *config.Config, s *settings) (generator.Config, error) {
+	genConfig := generator.DefaultConfig()
+	genConfig.Categories = cfg.WordLists()
+	genConfig.AllCategories = cfg.CategoryNames(false)
+	genConfig.CategoryWeights = cfg.CategoryWeights()
+	genConfig.WordWeights = cfg.WordWeights()
+	for _, fe := range cfg.Frontends {
+		genConfig.FrontendCodes = append(genConfig.FrontendCodes, fe.Code)
+	}
+	for _, be := range cfg.Backends {
+		genConfig.BackendCodes = append(genConfig.BackendCodes, be.Code)
+	}
+
+	strategy, err := generator.ParseSamplingStrategy(firstNonEmpty(s.sampling, cfg.Sampling))
+	if err != nil {
+		return genConfig, &OptionError{Option: "sampling", Err: err}
+	}
+	genConfig.Sampling = strategy
+
+	genConfig.PartsOfSpeech = make(map[string]generator.PartOfSpeech)
+	for category, tag := range cfg.PartsOfSpeech() {
+		pos, err := generator.ParsePartOfSpeech(tag)
+		if err != nil {
+			return genConfig, fmt.Errorf("category '%s': %w", category, err)
+		}
+		genConfig.PartsOfSpeech[category] = pos
+	}
+
+	if compose := firstNonEmpty(s.compose, cfg.Compose); compose != "" {
+		composition, err := generator.ParseComposition(compose)
+		if err != nil {
+			return genConfig, &OptionError{Option: "composition", Err: err}
+		}
+		genConfig.Composition = composition
+	}
+
+	style, err := generator.ParseCaseStyle(firstNonEmpty(s.caseStyle, cfg.Case))
+	if err != nil {
+		return genConfig, &OptionError{Option: "case", Err: err}
+	}
+	genConfig.CaseStyle = style
+
+	separator := firstNonEmpty(s.separator, cfg.Separator)
+	if err := generator.ValidateSeparator(separator); err != nil {
+		return genConfig, &OptionError{Option: "separator", Err: err}
+	}
+	genConfig.Separator = separator
+
+	genConfig.MaxLength = firstNonZero(s.maxLength, cfg.MaxLength)
+	genConfig.MaxWordLength = firstNonZero(s.maxWordLength, cfg.MaxWordLength)
+	genConfig.MaxCodeLength = firstNonZero(s.maxCodeLength, cfg.MaxCodeLength)
+	if genConfig.MaxLength < 0 || genConfig.MaxWordLength < 0 || genConfig.MaxCodeLength < 0 {
+		return genConfig, &OptionError{Option: "max length", Err: fmt.Errorf("length limits must not be negative")}
+	}
+
+	genConfig.SuffixDigits = firstNonZero(s.suffixDigits, cfg.SuffixDigits)
+	if genConfig.SuffixDigits < 0 {
+		return genConfig, &OptionError{Option: "suffix digits", Err: fmt.Errorf("suffix digits must not be negative")}
+	}
+
+	genConfig.UseEmojis = boolOr(s.emojis, cfg.Emojis)
+	genConfig.Emojis = cfg.WordEmojis()
+	genConfig.IncludeNumbers = boolOr(s.numbers, cfg.NumbersEnabled())
+
+	profile, err := utils.ParsePortability(firstNonEmpty(s.portability, cfg.Portability))
+	if err != nil {
+		return genConfig, &OptionError{Option: "portability", Err: err}
+	}
+	genConfig.Portability = profile
+
+	alliteration, err := generator.ParseAlliteration(firstNonEmpty(s.alliterate, cfg.Alliterate))
+	if err != nil {
+		return genConfig, &OptionError{Option: "alliteration", Err: err}
+	}
+	genConfig.Alliteration = alliteration
+
+	if s.wordMatch != "" {
+		genConfig.WordMatch, err = regexp.Compile(s.wordMatch)
+		if err != nil {
+			return genConfig, &OptionError{Option: "word-match", Err: err}
+		}
+	}
+
+	genConfig.Blocklist = generator.NewBlocklist(cfg.Blocklist.Words, cfg.Blocklist.Substrings,
+		boolOr(s.safeForWork, cfg.SafeForWorkEnabled()))
+
+	return genConfig, nil
+}
+
+// Next returns one unique name
+func (g *Generator) Next(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.gen.GenerateName(g.layout, g.sel, generator.SuffixType(g.settings.SuffixType), g.settings.SuffixLength)
+}
+
+// Generate returns Count unique names. On error it returns the names generated so far.
+func (g *Generator) Generate(ctx context.Context) ([]string, error) {
+	names := make([]string, 0, min(g.settings.Count, 1024))
+	for name, err := range g.Names(ctx) {
+		if err != nil {
+			return names, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Names streams Count unique names without collecting them, so large batches
+// only keep the set of issued names in memory. The first error ends the
+// stream and is yielded with an empty name.
+func (g *Generator) Names(ctx context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for i := 0; i < g.settings.Count; i++ {
+			name, err := g.Next(ctx)
+			if !yield(name, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// CreateNext generates a unique name and creates it as a directory in the
+// target directory. If another process creates the same name first, a new
+// name is generated, so concurrent runs never share a directory.
+func (g *Generator) CreateNext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.gen.CreateName(g.layout, g.sel, generator.SuffixType(g.settings.SuffixType), g.settings.SuffixLength)
+}
+
+// Create creates Count directories. On error it returns the names created so far.
+func (g *Generator) Create(ctx context.Context) ([]string, error) {
+	names := make([]string, 0, g.settings.Count)
+	for i := 0; i < g.settings.Count; i++ {
+		name, err := g.CreateNext(ctx)
+		if err != nil {
+			return names, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Seed returns the effective random seed, which reproduces this generator's output
+func (g *Generator) Seed() int64 {
+	return g.gen.Seed()
+}
+
+// Settings returns the effective settings of the generator
+func (g *Generator) Settings() Settings {
+	return g.settings
+}
+
+// Rejections returns how many words and suffixes the blocklist rejected so far
+func (g *Generator) Rejections() Rejections {
+	return g.gen.Rejections()
+}
+
+// Reserved returns the names found to already exist or passed to WithReserved, sorted
+func (g *Generator) Reserved() []string {
+	return g.gen.Reserved()
+}
+
+// SequenceStarts returns the highest existing number found for every sequence prefix
+func (g *Generator) SequenceStarts() map[string]int {
+	return g.gen.SequenceStarts()
+}
+
+// Parse splits a name into the frontend, backend, word, date and suffixes of
+// the generator's layout. Words, including hyphenated ones such as
+// "cherry-pick", are looked up in the config to find their category. When a
+// suffix fits several types, the type given to WithSuffix is preferred.
+func (g *Generator) Parse(name string) (Parsed, error) {
+	return g.gen.ParseName(g.layout, name, g.suffixHint)
+}
+
+// Stats counts the names the generator's layout and suffix can produce for a
+// category. An empty category uses the generator's category.
+func (g *Generator) Stats(category string) Stats {
+	if category == "" {
+		category = g.settings.Category
+	}
+	return g.gen.SpaceStats(g.layout, category, generator.SuffixType(g.settings.SuffixType), g.settings.SuffixLength)
+}
+
+// ReadWordList reads one word per line, ignoring blank lines and "#" comments.
+// Invalid entries are reported with name and line number.
+func ReadWordList(r io.Reader, name string) ([]string, error) {
+	return config.ReadWordList(r, name)
+}
+
+// ReadWordFile reads a word list file, or standard input for "-"
+func ReadWordFile(path string) ([]string, error) {
+	return config.ReadWordFile(path)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstNonZero(values ...int) int {
+	for _, v := range values {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+func boolOr(value *bool, fallback bool) bool {
+	if value != nil {
+		return *value
+	}
+	return fallback
+}
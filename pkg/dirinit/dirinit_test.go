@@ -0,0 +1,164 @@
+package dirinit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testConfig is a config with a single category, so names are predictable
+func testConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.Categories = map[string]Category{"food": {Words: []string{"pizza"}}}
+	return cfg
+}
+
+func TestNewSuffixLength(t *testing.T) {
+	tests := []struct {
+		suffixType string
+		length     int
+		want       int
+	}{
+		{"mixed", 0, 4},
+		{"alpha", 6, 6},
+		{"hex", 99, 16},
+		{"ulid", 0, 12},
+		{"uuid7", 0, 20},
+		{"uuid7", 8, 20},
+		{"date", 0, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.suffixType, func(t *testing.T) {
+			g, err := New(WithConfig(testConfig()), WithSuffix(tt.suffixType, tt.length), WithoutDiskCheck())
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if got := g.Settings().SuffixLength; got != tt.want {
+				t.Errorf("SuffixLength = %d, want %d", got, tt.want)
+			}
+
+			name, err := g.Next(context.Background())
+			if err != nil {
+				t.Fatalf("Next: %v", err)
+			}
+			if suffix := strings.TrimPrefix(name, "pizza-"); len(suffix) != tt.want {
+				t.Errorf("Next() = %q, want a suffix of %d characters", name, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOptionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		opt    Option
+		option string
+	}{
+		{"count", WithCount(0), "count"},
+		{"count too large", WithCount(1_000_001), "count"},
+		{"suffix", WithSuffix("nope", 0), "suffix"},
+		{"layout", WithLayout("{noun}"), "layout"},
+		{"category", WithCategory("space"), "category"},
+		{"word list", WithWordList("all", []string{"pizza"}), "word-list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithConfig(testConfig()), tt.opt)
+			if !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("New() error = %v, want ErrInvalidOption", err)
+			}
+			var optErr *OptionError
+			if !errors.As(err, &optErr) || optErr.Option != tt.option {
+				t.Errorf("New() error = %v, want an OptionError for %s", err, tt.option)
+			}
+		})
+	}
+}
+
+func TestNewWritesNothing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("DIR_INIT_CONFIG", "")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(cfg.Categories) == 0 {
+		t.Errorf("LoadConfig() without a config file has no categories")
+	}
+
+	g, err := New(WithCount(3), WithoutDiskCheck())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := g.Generate(context.Background()); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	err = filepath.WalkDir(home, func(path string, d os.DirEntry, err error) error {
+		if err == nil && path != home {
+			t.Errorf("wrote %s", path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerateConcurrent(t *testing.T) {
+	g, err := New(WithConfig(testConfig()), WithSuffix("alpha", 3), WithCount(200), WithoutDiskCheck())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	const workers = 8
+	batches := make([][]string, workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Half of the workers stream, the other half collect
+			if i%2 == 0 {
+				for name, err := range g.Names(context.Background()) {
+					if err != nil {
+						t.Errorf("Names: %v", err)
+						return
+					}
+					batches[i] = append(batches[i], name)
+				}
+				return
+			}
+			names, err := g.Generate(context.Background())
+			if err != nil {
+				t.Errorf("Generate: %v", err)
+			}
+			batches[i] = names
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for i, names := range batches {
+		if len(names) != 200 {
+			t.Errorf("worker %d got %d names, want 200", i, len(names))
+		}
+		for _, name := range names {
+			if seen[name] {
+				t.Errorf("%q was generated twice", name)
+			}
+			seen[name] = true
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Package dirinit generates funny, unique directory names such as
+// "pizza-a1b2" or "rct-node-goofy-penguin-x9k2", and creates them.
+//
+// It is the library behind the dir-init CLI and reads the same config file,
+// so names generated here follow the conventions of the CLI users:
+//
+//	gen, err := dirinit.New(dirinit.WithCategory("food"), dirinit.WithCount(3))
+//	if err != nil {
+//		return err
+//	}
+//	names, err := gen.Generate(ctx)
+//
+// Config files are only ever read: New loads the CLI's config without
+// creating or upgrading it, or takes the one given with WithConfig.
+//
+// # Compatibility
+//
+// The exported API of this package follows semantic versioning. Within a
+// major version, functions, options, types, fields and sentinel errors are
+// only added: existing ones keep their signatures and their meaning. Packages
+// under internal are not part of the API and may change at any time.
+//
+// Error messages are meant for people and may be reworded in any release;
+// match errors with errors.Is and errors.As instead of comparing their text.
+//
+// Config files written by earlier releases keep loading, and settings that
+// are missing from them keep their previous defaults. The names a seed gives
+// are only reproducible with the same release, config, options and clock:
+// releases may add built-in words or change how words and suffixes are picked.
+package dirinit
@@ -0,0 +1,49 @@
+package dirinit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aravindcm49/dir-init/internal/generator"
+)
+
+var (
+	// ErrInvalidOption is matched by every OptionError
+	ErrInvalidOption = errors.New("invalid option")
+
+	// ErrNameSpaceExhausted is matched by ExhaustedError: no unused name was
+	// found within the allowed attempts
+	ErrNameSpaceExhausted = generator.ErrNameSpaceExhausted
+
+	// ErrLengthBudget is matched by LengthBudgetError: no name fits in the maximum length
+	ErrLengthBudget = generator.ErrLengthBudget
+)
+
+// ExhaustedError reports how many combinations were left when generation gave up
+type ExhaustedError = generator.ExhaustedError
+
+// LengthBudgetError reports the shortest name a layout could produce when it exceeds the maximum length
+type LengthBudgetError = generator.LengthBudgetError
+
+// OptionError reports an option whose value cannot be used
+type OptionError struct {
+	Option string // name of the option, such as "suffix" or "layout"
+	Err    error
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("invalid %s option: %v", e.Option, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// Is lets errors.Is match OptionError against ErrInvalidOption
+func (e *OptionError) Is(target error) bool {
+	return target == ErrInvalidOption
+}
+
+// CreateError reports a directory that could not be created
+type CreateError = generator.CreateError
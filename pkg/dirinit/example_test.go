@@ -0,0 +1,66 @@
+package dirinit_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/aravindcm49/dir-init/pkg/dirinit"
+)
+
+func Example() {
+	cfg := dirinit.DefaultConfig()
+	cfg.Categories = map[string]dirinit.Category{"food": {Words: []string{"pizza"}}}
+
+	gen, err := dirinit.New(
+		dirinit.WithConfig(cfg),
+		dirinit.WithSuffix("sequence", 3),
+		dirinit.WithCount(3),
+		dirinit.WithoutDiskCheck(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	names, err := gen.Generate(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	// Output:
+	// pizza-001
+	// pizza-002
+	// pizza-003
+}
+
+func ExampleGenerator_Names() {
+	cfg := dirinit.DefaultConfig()
+	cfg.Categories = map[string]dirinit.Category{"food": {Words: []string{"pizza", "taco"}}}
+
+	gen, err := dirinit.New(
+		dirinit.WithConfig(cfg),
+		dirinit.WithLayout("{word}"),
+		dirinit.WithCount(3),
+		dirinit.WithoutDiskCheck(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Two words only make two names, so the stream ends with an error
+	found := 0
+	for _, err := range gen.Names(context.Background()) {
+		if errors.Is(err, dirinit.ErrNameSpaceExhausted) {
+			fmt.Println("found", found, "names")
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		found++
+	}
+	// Output: found 2 names
+}
@@ -0,0 +1,222 @@
+package dirinit
+
+import "time"
+
+// Option configures a Generator. Options override the values of the config file.
+type Option func(*settings)
+
+// settings collects the options before New validates them
+type settings struct {
+	config *Config
+
+	category     string
+	suffixType   string
+	suffixLength int
+	suffixSet    bool // WithSuffix was given, so Parse prefers the suffix type
+	count        int
+	seed         int64
+	layout       string
+	frontend     string
+	backend      string
+	word         string
+	targetDir    string
+	now          time.Time
+	maxAttempts  int
+
+	sampling      string
+	compose       string
+	caseStyle     string
+	separator     string
+	maxLength     int
+	maxWordLength int
+	maxCodeLength int
+	suffixDigits  int
+	emojis        *bool
+	numbers       *bool
+	safeForWork   *bool
+	portability   string
+	alliterate    string
+	wordMatch     string
+	wordLists     map[string][]string
+
+	skipDiskCheck  bool
+	reserved       []string
+	sequenceStarts map[string]int
+}
+
+func defaultSettings() settings {
+	return settings{
+		category:   "all",
+		suffixType: "mixed",
+		count:      1,
+	}
+}
+
+// WithConfig uses cfg instead of loading the config file of the dir-init CLI
+func WithConfig(cfg *Config) Option {
+	return func(s *settings) { s.config = cfg }
+}
+
+// WithCategory picks words from a category expression: one category, "all"
+// (the default), a union such as "food,animals" or exclusions such as "all,!dev"
+func WithCategory(category string) Option {
+	return func(s *settings) { s.category = category }
+}
+
+// WithSuffix sets the suffix type, such as "mixed" (the default) or "ulid", and
+// its length. Lengths outside the range of the type are clamped, and 0 uses
+// the default length of the type (see SuffixSpec.DefaultLength).
+func WithSuffix(suffixType string, length int) Option {
+	return func(s *settings) {
+		s.suffixType = suffixType
+		s.suffixLength = length
+		s.suffixSet = true
+	}
+}
+
+// WithCount sets how many names Generate and Create return, 1 by default
+func WithCount(n int) Option {
+	return func(s *settings) { s.count = n }
+}
+
+// WithSeed makes the output reproducible; 0 (the default) picks a random seed
+func WithSeed(seed int64) Option {
+	return func(s *settings) { s.seed = seed }
+}
+
+// WithLayout sets the name layout, such as "{frontend}-{backend}-{word}{suffix}".
+// Without it the config file's layout or DefaultLayout is used.
+func WithLayout(layout string) Option {
+	return func(s *settings) { s.layout = layout }
+}
+
+// WithFrontend sets the code of the {frontend} layout token
+func WithFrontend(code string) Option {
+	return func(s *settings) { s.frontend = code }
+}
+
+// WithBackend sets the code of the {backend} layout token
+func WithBackend(code string) Option {
+	return func(s *settings) { s.backend = code }
+}
+
+// WithWord uses a fixed word instead of picking one from a category
+func WithWord(word string) Option {
+	return func(s *settings) { s.word = word }
+}
+
+// WithTargetDir sets the directory names are checked against and created in,
+// the working directory by default
+func WithTargetDir(dir string) Option {
+	return func(s *settings) { s.targetDir = dir }
+}
+
+// WithNow fixes the clock used for {date} tokens and time-based suffixes,
+// which together with WithSeed reproduces a run exactly
+func WithNow(now time.Time) Option {
+	return func(s *settings) { s.now = now }
+}
+
+// WithMaxAttempts sets how often a name is re-rolled before giving up
+func WithMaxAttempts(n int) Option {
+	return func(s *settings) { s.maxAttempts = n }
+}
+
+// WithSampling sets the sampling strategy: uniform-category, uniform-word or weighted
+func WithSampling(strategy string) Option {
+	return func(s *settings) { s.sampling = strategy }
+}
+
+// WithComposition builds words from parts of speech, such as "adj-noun"
+func WithComposition(pattern string) Option {
+	return func(s *settings) { s.compose = pattern }
+}
+
+// WithCase sets the case style: kebab, snake, camel, pascal, dot or upper
+func WithCase(style string) Option {
+	return func(s *settings) { s.caseStyle = style }
+}
+
+// WithSeparator replaces the separator between name parts
+func WithSeparator(separator string) Option {
+	return func(s *settings) { s.separator = separator }
+}
+
+// WithMaxLength keeps names within n bytes, shortening words, suffixes and codes to fit
+func WithMaxLength(n int) Option {
+	return func(s *settings) { s.maxLength = n }
+}
+
+// WithMaxWordLength skips words longer than n
+func WithMaxWordLength(n int) Option {
+	return func(s *settings) { s.maxWordLength = n }
+}
+
+// WithMaxCodeLength abbreviates frontend and backend codes longer than n
+func WithMaxCodeLength(n int) Option {
+	return func(s *settings) { s.maxCodeLength = n }
+}
+
+// WithSuffixDigits ends pronounceable suffixes with n digits
+func WithSuffixDigits(n int) Option {
+	return func(s *settings) { s.suffixDigits = n }
+}
+
+// WithEmojis prefixes names with the emoji of their word when enabled
+func WithEmojis(enabled bool) Option {
+	return func(s *settings) { s.emojis = &enabled }
+}
+
+// WithNumbers allows or forbids digits in suffixes
+func WithNumbers(enabled bool) Option {
+	return func(s *settings) { s.numbers = &enabled }
+}
+
+// WithSafeForWork turns the built-in safe for work filter on or off
+func WithSafeForWork(enabled bool) Option {
+	return func(s *settings) { s.safeForWork = &enabled }
+}
+
+// WithPortability restricts the characters of names: native, ascii or posix
+func WithPortability(profile string) Option {
+	return func(s *settings) { s.portability = profile }
+}
+
+// WithAlliteration picks words starting with the initial of the frontend or
+// backend code: any, frontend, backend or both
+func WithAlliteration(mode string) Option {
+	return func(s *settings) { s.alliterate = mode }
+}
+
+// WithWordMatch only picks words matching a regular expression, such as "^p"
+func WithWordMatch(pattern string) Option {
+	return func(s *settings) { s.wordMatch = pattern }
+}
+
+// WithWordList adds words to a category for this Generator only, creating the
+// category when the config does not have it. Select it with WithCategory.
+func WithWordList(category string, words []string) Option {
+	return func(s *settings) {
+		if s.wordLists == nil {
+			s.wordLists = make(map[string][]string)
+		}
+		s.wordLists[category] = append(s.wordLists[category], words...)
+	}
+}
+
+// WithoutDiskCheck only avoids names issued by the Generator or passed to
+// WithReserved, ignoring what exists in the target directory
+func WithoutDiskCheck() Option {
+	return func(s *settings) { s.skipDiskCheck = true }
+}
+
+// WithReserved marks names as taken, as if they existed in the target directory
+func WithReserved(names ...string) Option {
+	return func(s *settings) { s.reserved = append(s.reserved, names...) }
+}
+
+// WithSequenceStarts sets the highest existing number of each sequence
+// prefix, instead of scanning the target directory
+func WithSequenceStarts(starts map[string]int) Option {
+	return func(s *settings) { s.sequenceStarts = starts }
+}